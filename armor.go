@@ -0,0 +1,102 @@
+/*******************************************************************
+
+		::          ::        +--------+-----------------------+
+		  ::      ::          | Author | Dmitry Novikov        |
+		::::::::::::::        | Email  | dredfort.42@gmail.com |
+	  ::::  ::::::  ::::      +--------+-----------------------+
+	::::::::::::::::::::::
+	::  ::::::::::::::  ::    File     | armor.go
+	::  ::          ::  ::    Created  | 2026-10-16
+		  ::::  ::::          Modified | 2026-10-16
+
+	GitHub:   https://github.com/dredfort42
+	LinkedIn: https://linkedin.com/in/novikov-da
+
+*******************************************************************/
+
+package licenser
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// pemTypeLicense is the PEM block type of armored licenses.
+const pemTypeLicense = "LICENSE"
+
+// EncodeLicensePEM armors a signed license as a "-----BEGIN LICENSE-----"
+// block. The body is the JSON-encoded signed license; the customer, application
+// and dates are repeated as PEM headers for people reading the file. The
+// headers are not signed and are ignored when the license is loaded.
+func EncodeLicensePEM(signedLicense *SignedLicense) ([]byte, error) {
+	body, err := json.Marshal(signedLicense)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal license: %w", err)
+	}
+
+	license := &signedLicense.Data
+
+	block := &pem.Block{
+		Type: pemTypeLicense,
+		Headers: map[string]string{
+			"Customer": headerValue(license.Customer),
+			"App-ID":   headerValue(license.AppID),
+			"Issued":   time.Unix(license.IssuedAt, 0).Format(TimestampLayout),
+			"Expires":  FormatExpiry(license.ExpiresAt),
+		},
+		Bytes: body,
+	}
+
+	if license.NotBefore > 0 {
+		block.Headers["Not-Before"] = time.Unix(license.NotBefore, 0).Format(TimestampLayout)
+	}
+
+	var buf bytes.Buffer
+	if err := pem.Encode(&buf, block); err != nil {
+		return nil, fmt.Errorf("failed to armor license: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// DecodeLicensePEM returns the first armored license in data. Other PEM
+// blocks, such as a public key concatenated into the same file, are skipped.
+func DecodeLicensePEM(data []byte) (*SignedLicense, error) {
+	rest := data
+
+	for {
+		var block *pem.Block
+
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, fmt.Errorf("%w: no PEM %s block found", ErrUnsupportedFormat, pemTypeLicense)
+		}
+
+		if block.Type != pemTypeLicense {
+			continue
+		}
+
+		var signedLicense SignedLicense
+		if err := json.Unmarshal(block.Bytes, &signedLicense); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal license: %w", err)
+		}
+
+		return &signedLicense, nil
+	}
+}
+
+// headerValue keeps a PEM header value on a single line.
+func headerValue(value string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+
+		return r
+	}, value)
+}
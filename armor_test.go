@@ -0,0 +1,131 @@
+/*******************************************************************
+
+		::          ::        +--------+-----------------------+
+		  ::      ::          | Author | Dmitry Novikov        |
+		::::::::::::::        | Email  | dredfort.42@gmail.com |
+	  ::::  ::::::  ::::      +--------+-----------------------+
+	::::::::::::::::::::::
+	::  ::::::::::::::  ::    File     | armor_test.go
+	::  ::          ::  ::    Created  | 2026-10-16
+		  ::::  ::::          Modified | 2026-10-16
+
+	GitHub:   https://github.com/dredfort42
+	LinkedIn: https://linkedin.com/in/novikov-da
+
+*******************************************************************/
+
+package licenser_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	licenser "github.com/dredfort42/go_licenser"
+)
+
+func TestArmoredLicense(t *testing.T) {
+	manager := newEd25519Manager(t, licenser.Config{FileFormat: licenser.FileFormatPEM})
+
+	license := licenser.License{
+		Customer:  "Armored Customer\nInjected: header",
+		AppID:     "armored-app",
+		Services:  []licenser.Service{{ID: "test", Name: "Test"}},
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	}
+
+	signedLicense, err := manager.GenerateLicense(&license)
+	if err != nil {
+		t.Fatalf("Failed to generate license: %v", err)
+	}
+
+	dir := t.TempDir()
+	licensePath := filepath.Join(dir, "license.pem")
+
+	if err := manager.SaveLicense(signedLicense, licensePath); err != nil {
+		t.Fatalf("Failed to save license: %v", err)
+	}
+
+	data, err := os.ReadFile(licensePath)
+	if err != nil {
+		t.Fatalf("Failed to read license: %v", err)
+	}
+
+	t.Run("Armor", func(t *testing.T) {
+		text := string(data)
+
+		if !strings.HasPrefix(text, "-----BEGIN LICENSE-----\n") || !contains(text, "-----END LICENSE-----") {
+			t.Errorf("Expected an armored license, got %s", text)
+		}
+
+		if !contains(text, "Customer: Armored Customer Injected: header\n") {
+			t.Errorf("Expected a single-line customer header, got %s", text)
+		}
+
+		if !contains(text, "Expires: "+licenser.FormatExpiry(license.ExpiresAt)) {
+			t.Errorf("Expected an expiry header, got %s", text)
+		}
+	})
+
+	t.Run("LoadArmored", func(t *testing.T) {
+		// JSON validators detect the armor without extra configuration.
+		validator, err := licenser.NewManager(licenser.Config{PublicKeyPEM: exportPublicKey(t, manager)})
+		if err != nil {
+			t.Fatalf("Failed to create validator: %v", err)
+		}
+
+		loaded, result, err := validator.LoadAndValidateLicense(licensePath)
+		if err != nil {
+			t.Fatalf("Failed to load license: %v", err)
+		}
+
+		if !result.Valid {
+			t.Errorf("Armored license should be valid, errors: %v", result.Errors)
+		}
+
+		if loaded.Data.Customer != license.Customer {
+			t.Errorf("Expected customer '%s', got '%s'", license.Customer, loaded.Data.Customer)
+		}
+	})
+
+	t.Run("ConcatenatedPEM", func(t *testing.T) {
+		bundlePath := filepath.Join(dir, "bundle.pem")
+		bundle := exportPublicKey(t, manager) + "\n" + string(data)
+
+		if err := os.WriteFile(bundlePath, []byte(bundle), 0600); err != nil {
+			t.Fatalf("Failed to write bundle: %v", err)
+		}
+
+		_, result, err := manager.LoadAndValidateLicense(bundlePath)
+		if err != nil {
+			t.Fatalf("Failed to load license from bundle: %v", err)
+		}
+
+		if !result.Valid {
+			t.Errorf("License from bundle should be valid, errors: %v", result.Errors)
+		}
+	})
+
+	t.Run("EditedHeaders", func(t *testing.T) {
+		edited := strings.Replace(string(data), "Customer: Armored Customer", "Customer: Someone Else", 1)
+
+		loaded, err := licenser.DecodeLicensePEM([]byte(edited))
+		if err != nil {
+			t.Fatalf("Failed to decode license: %v", err)
+		}
+
+		if loaded.Data.Customer != license.Customer {
+			t.Errorf("Headers should not affect license data, got customer '%s'", loaded.Data.Customer)
+		}
+	})
+
+	t.Run("NoLicenseBlock", func(t *testing.T) {
+		_, err := licenser.DecodeLicensePEM([]byte(exportPublicKey(t, manager)))
+		if !errors.Is(err, licenser.ErrUnsupportedFormat) {
+			t.Errorf("Expected ErrUnsupportedFormat, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,360 @@
+/*******************************************************************
+
+		::          ::        +--------+-----------------------+
+		  ::      ::          | Author | Dmitry Novikov        |
+		::::::::::::::        | Email  | dredfort.42@gmail.com |
+	  ::::  ::::::  ::::      +--------+-----------------------+
+	::::::::::::::::::::::
+	::  ::::::::::::::  ::    File     | cbor.go
+	::  ::          ::  ::    Created  | 2026-10-16
+		  ::::  ::::          Modified | 2026-10-16
+
+	GitHub:   https://github.com/dredfort42
+	LinkedIn: https://linkedin.com/in/novikov-da
+
+*******************************************************************/
+
+package licenser
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// This file implements the subset of CBOR (RFC 8949) needed for COSE:
+// integers, byte and text strings, arrays, maps, tags, booleans, null and
+// floats. Maps are encoded with deterministically sorted keys; indefinite
+// lengths are rejected when decoding.
+
+// CBOR major types.
+const (
+	cborUnsigned byte = iota << 5
+	cborNegative
+	cborBytes
+	cborText
+	cborArray
+	cborMap
+	cborTag
+	cborSimple
+)
+
+// cborMaxDepth limits nesting when decoding untrusted input.
+const cborMaxDepth = 32
+
+var errCBOR = errors.New("malformed CBOR")
+
+// cborTagged is a decoded CBOR tag and its content.
+type cborTagged struct {
+	Number  uint64
+	Content any
+}
+
+// cborMapEntry is a map entry in the order it has to be encoded.
+type cborMapEntry struct {
+	key   []byte
+	value any
+}
+
+func cborAppendHead(buf []byte, major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return append(buf, major|byte(n))
+	case n <= math.MaxUint8:
+		return append(buf, major|24, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, major|25), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(buf, major|26), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, major|27), n)
+	}
+}
+
+// cborMarshal encodes v, which may be built from nil, bool, int, int64,
+// uint64, float64, string, []byte, []any, map[any]any and cborTagged values.
+func cborMarshal(v any) ([]byte, error) {
+	return cborAppend(nil, v)
+}
+
+func cborAppend(buf []byte, v any) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return append(buf, cborSimple|22), nil
+	case bool:
+		if v {
+			return append(buf, cborSimple|21), nil
+		}
+
+		return append(buf, cborSimple|20), nil
+	case int:
+		return cborAppendInt(buf, int64(v)), nil
+	case int64:
+		return cborAppendInt(buf, v), nil
+	case uint64:
+		return cborAppendHead(buf, cborUnsigned, v), nil
+	case float64:
+		return binary.BigEndian.AppendUint64(append(buf, cborSimple|27), math.Float64bits(v)), nil
+	case string:
+		return append(cborAppendHead(buf, cborText, uint64(len(v))), v...), nil
+	case []byte:
+		return append(cborAppendHead(buf, cborBytes, uint64(len(v))), v...), nil
+	case []any:
+		buf = cborAppendHead(buf, cborArray, uint64(len(v)))
+
+		for _, item := range v {
+			var err error
+			if buf, err = cborAppend(buf, item); err != nil {
+				return nil, err
+			}
+		}
+
+		return buf, nil
+	case map[any]any:
+		return cborAppendMap(buf, v)
+	case cborTagged:
+		return cborAppend(cborAppendHead(buf, cborTag, v.Number), v.Content)
+	default:
+		return nil, fmt.Errorf("cannot encode %T as CBOR", v)
+	}
+}
+
+func cborAppendInt(buf []byte, n int64) []byte {
+	if n < 0 {
+		return cborAppendHead(buf, cborNegative, uint64(-(n + 1)))
+	}
+
+	return cborAppendHead(buf, cborUnsigned, uint64(n))
+}
+
+// cborAppendMap sorts entries by their encoded keys (RFC 8949 section 4.2.1).
+func cborAppendMap(buf []byte, m map[any]any) ([]byte, error) {
+	entries := make([]cborMapEntry, 0, len(m))
+
+	for key, value := range m {
+		encoded, err := cborMarshal(key)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, cborMapEntry{key: encoded, value: value})
+	}
+
+	slices.SortFunc(entries, func(a, b cborMapEntry) int { return bytes.Compare(a.key, b.key) })
+
+	buf = cborAppendHead(buf, cborMap, uint64(len(entries)))
+
+	for _, entry := range entries {
+		buf = append(buf, entry.key...)
+
+		var err error
+		if buf, err = cborAppend(buf, entry.value); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf, nil
+}
+
+// cborUnmarshal decodes a single CBOR item that must span all of data.
+// Integers decode as int64, maps as map[any]any and arrays as []any.
+func cborUnmarshal(data []byte) (any, error) {
+	d := cborDecoder{data: data}
+
+	v, err := d.decode(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.pos != len(data) {
+		return nil, fmt.Errorf("%w: trailing data", errCBOR)
+	}
+
+	return v, nil
+}
+
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *cborDecoder) head() (byte, uint64, error) {
+	if d.pos >= len(d.data) {
+		return 0, 0, fmt.Errorf("%w: unexpected end of data", errCBOR)
+	}
+
+	initial := d.data[d.pos]
+	d.pos++
+
+	major, info := initial&0xe0, initial&0x1f
+
+	var size int
+
+	switch {
+	case info < 24:
+		return major, uint64(info), nil
+	case info <= 27:
+		size = 1 << (info - 24)
+	default:
+		return 0, 0, fmt.Errorf("%w: unsupported additional information %d", errCBOR, info)
+	}
+
+	if len(d.data)-d.pos < size {
+		return 0, 0, fmt.Errorf("%w: unexpected end of data", errCBOR)
+	}
+
+	var n uint64
+	for _, b := range d.data[d.pos : d.pos+size] {
+		n = n<<8 | uint64(b)
+	}
+
+	d.pos += size
+
+	return major, n, nil
+}
+
+func (d *cborDecoder) bytes(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, fmt.Errorf("%w: unexpected end of data", errCBOR)
+	}
+
+	b := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+
+	return b, nil
+}
+
+func (d *cborDecoder) decode(depth int) (any, error) {
+	if depth > cborMaxDepth {
+		return nil, fmt.Errorf("%w: nesting too deep", errCBOR)
+	}
+
+	start := d.pos
+
+	major, n, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case cborUnsigned:
+		if n > math.MaxInt64 {
+			return nil, fmt.Errorf("%w: integer overflow", errCBOR)
+		}
+
+		return int64(n), nil
+	case cborNegative:
+		if n > math.MaxInt64 {
+			return nil, fmt.Errorf("%w: integer overflow", errCBOR)
+		}
+
+		return -int64(n) - 1, nil
+	case cborBytes:
+		b, err := d.bytes(n)
+
+		return bytes.Clone(b), err
+	case cborText:
+		b, err := d.bytes(n)
+
+		return string(b), err
+	case cborArray:
+		// Every item takes at least one byte.
+		if n > uint64(len(d.data)-d.pos) {
+			return nil, fmt.Errorf("%w: unexpected end of data", errCBOR)
+		}
+
+		items := make([]any, 0, n)
+
+		for range n {
+			item, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+
+			items = append(items, item)
+		}
+
+		return items, nil
+	case cborMap:
+		if n > uint64(len(d.data)-d.pos)/2 {
+			return nil, fmt.Errorf("%w: unexpected end of data", errCBOR)
+		}
+
+		m := make(map[any]any, n)
+
+		for range n {
+			key, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, fmt.Errorf("%w: unsupported map key type %T", errCBOR, key)
+			}
+
+			if _, ok := m[key]; ok {
+				return nil, fmt.Errorf("%w: duplicate map key %v", errCBOR, key)
+			}
+
+			if m[key], err = d.decode(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+
+		return m, nil
+	case cborTag:
+		content, err := d.decode(depth + 1)
+
+		return cborTagged{Number: n, Content: content}, err
+	default:
+		return d.simple(d.data[start]&0x1f, n)
+	}
+}
+
+func (d *cborDecoder) simple(info byte, n uint64) (any, error) {
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22:
+		return nil, nil
+	case 25:
+		return float16ToFloat64(uint16(n)), nil
+	case 26:
+		return float64(math.Float32frombits(uint32(n))), nil
+	case 27:
+		return math.Float64frombits(n), nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported simple value %d", errCBOR, info)
+	}
+}
+
+// float16ToFloat64 converts an IEEE 754 half-precision float.
+func float16ToFloat64(h uint16) float64 {
+	sign := 1.0
+	if h&0x8000 != 0 {
+		sign = -1
+	}
+
+	exponent := int(h>>10) & 0x1f
+	fraction := float64(h & 0x3ff)
+
+	switch exponent {
+	case 0:
+		return sign * math.Ldexp(fraction, -24)
+	case 0x1f:
+		if fraction == 0 {
+			return math.Inf(int(sign))
+		}
+
+		return math.NaN()
+	default:
+		return sign * math.Ldexp(fraction+1024, exponent-25)
+	}
+}
@@ -0,0 +1,118 @@
+/*******************************************************************
+
+		::          ::        +--------+-----------------------+
+		  ::      ::          | Author | Dmitry Novikov        |
+		::::::::::::::        | Email  | dredfort.42@gmail.com |
+	  ::::  ::::::  ::::      +--------+-----------------------+
+	::::::::::::::::::::::
+	::  ::::::::::::::  ::    File     | clock.go
+	::  ::          ::  ::    Created  | 2026-10-16
+		  ::::  ::::          Modified | 2026-10-16
+
+	GitHub:   https://github.com/dredfort42
+	LinkedIn: https://linkedin.com/in/novikov-da
+
+*******************************************************************/
+
+package licenser
+
+import "time"
+
+// Clock supplies the current time to every time-dependent check, so tests
+// and audits can evaluate licenses as of a chosen moment.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts an ordinary function to the Clock interface.
+type ClockFunc func() time.Time
+
+// Now returns f().
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// SystemClock is the default Clock, backed by time.Now.
+var SystemClock Clock = ClockFunc(time.Now)
+
+// FixedClock returns a Clock that always reports t.
+func FixedClock(t time.Time) Clock {
+	return ClockFunc(func() time.Time { return t })
+}
+
+// Option configures NewBuilder and the package-level helpers.
+type Option func(*options)
+
+type options struct {
+	clock  Clock
+	window validityWindow
+}
+
+// WithClock makes a builder or helper read the current time from clock
+// instead of SystemClock.
+func WithClock(clock Clock) Option {
+	return func(o *options) {
+		o.clock = clock
+	}
+}
+
+// WithClockSkew makes a helper tolerate clocks that drift by up to skew, as
+// Config.ClockSkew does for validation.
+func WithClockSkew(skew time.Duration) Option {
+	return func(o *options) {
+		o.window.skew = skew
+	}
+}
+
+// WithGracePeriod makes a helper accept licenses for grace after they
+// expire, as Config.GracePeriod does for validation.
+func WithGracePeriod(grace time.Duration) Option {
+	return func(o *options) {
+		o.window.grace = grace
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{clock: SystemClock}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.clock == nil {
+		o.clock = SystemClock
+	}
+
+	return o
+}
+
+// validityWindow is the leeway applied to the validity period of a license.
+type validityWindow struct {
+	skew  time.Duration // Tolerated clock drift at both ends of the period
+	grace time.Duration // Time after expiry (and skew) during which the license is still accepted
+}
+
+// statusAt returns StatusActive, StatusGrace, StatusExpired or
+// StatusNotYetValid for license at now.
+func (w validityWindow) statusAt(license *License, now time.Time) string {
+	if license.ExpiresAt > 0 {
+		if now.Add(-w.skew-w.grace).Unix() > license.ExpiresAt {
+			return StatusExpired
+		}
+
+		if now.Add(-w.skew).Unix() > license.ExpiresAt {
+			return StatusGrace
+		}
+	}
+
+	if license.NotBefore > 0 && now.Add(w.skew).Unix() < license.NotBefore {
+		return StatusNotYetValid
+	}
+
+	return StatusActive
+}
+
+// graceEndsAt returns the last moment license is accepted.
+func (w validityWindow) graceEndsAt(license *License) time.Time {
+	return time.Unix(license.ExpiresAt, 0).Add(w.skew + w.grace)
+}
@@ -0,0 +1,251 @@
+/*******************************************************************
+
+		::          ::        +--------+-----------------------+
+		  ::      ::          | Author | Dmitry Novikov        |
+		::::::::::::::        | Email  | dredfort.42@gmail.com |
+	  ::::  ::::::  ::::      +--------+-----------------------+
+	::::::::::::::::::::::
+	::  ::::::::::::::  ::    File     | clock_test.go
+	::  ::          ::  ::    Created  | 2026-10-16
+		  ::::  ::::          Modified | 2026-10-16
+
+	GitHub:   https://github.com/dredfort42
+	LinkedIn: https://linkedin.com/in/novikov-da
+
+*******************************************************************/
+
+package licenser_test
+
+import (
+	"testing"
+	"time"
+
+	licenser "github.com/dredfort42/go_licenser"
+)
+
+func TestClock(t *testing.T) {
+	issuedAt := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	expiresAt := issuedAt.AddDate(1, 0, 0)
+	clock := licenser.WithClock(licenser.FixedClock(issuedAt))
+
+	license := licenser.NewBuilder(clock).
+		WithCustomer("Clock Customer").
+		WithAppID("clock-app").
+		WithService(licenser.Service{ID: "test", Name: "Test"}).
+		WithExpirationDuration(expiresAt.Sub(issuedAt)).
+		Build()
+
+	t.Run("Builder", func(t *testing.T) {
+		if license.IssuedAt != issuedAt.Unix() || license.ExpiresAt != expiresAt.Unix() {
+			t.Errorf("Expected issued %d and expires %d, got %d and %d",
+				issuedAt.Unix(), expiresAt.Unix(), license.IssuedAt, license.ExpiresAt)
+		}
+	})
+
+	manager := newEd25519Manager(t, licenser.Config{Clock: licenser.FixedClock(issuedAt)})
+
+	signedLicense, err := manager.GenerateLicense(&license)
+	if err != nil {
+		t.Fatalf("Failed to generate license: %v", err)
+	}
+
+	t.Run("Issuer", func(t *testing.T) {
+		if signedLicense.CreatedAt != issuedAt.Unix() {
+			t.Errorf("Expected created at %d, got %d", issuedAt.Unix(), signedLicense.CreatedAt)
+		}
+	})
+
+	t.Run("ValidateLicenseAt", func(t *testing.T) {
+		if result := manager.ValidateLicenseAt(signedLicense, expiresAt.Add(-time.Second)); !result.Valid {
+			t.Errorf("License should be valid before it expires, errors: %v", result.Errors)
+		}
+
+		result := manager.ValidateLicenseAt(signedLicense, expiresAt.Add(time.Second))
+		if result.Valid || !contains(result.Errors[0], "expired") {
+			t.Errorf("License should be expired after it expires, errors: %v", result.Errors)
+		}
+	})
+
+	t.Run("ConfigClock", func(t *testing.T) {
+		verifier, err := licenser.NewVerifier(licenser.Config{
+			PublicKeyPEM: exportPublicKey(t, manager),
+			Clock:        licenser.FixedClock(expiresAt.Add(-36 * time.Hour)),
+		})
+		if err != nil {
+			t.Fatalf("Failed to create verifier: %v", err)
+		}
+
+		if result := verifier.ValidateLicense(signedLicense); !result.Valid {
+			t.Errorf("License should be valid, errors: %v", result.Errors)
+		}
+
+		if info := verifier.GetLicenseInfo(&license); info.TimeUntilExpiry != "1d 12h" {
+			t.Errorf("Expected '1d 12h' until expiry, got '%s'", info.TimeUntilExpiry)
+		}
+
+		late, err := licenser.NewVerifier(licenser.Config{
+			PublicKeyPEM: exportPublicKey(t, manager),
+			Clock:        licenser.ClockFunc(func() time.Time { return expiresAt.Add(time.Minute) }),
+		})
+		if err != nil {
+			t.Fatalf("Failed to create verifier: %v", err)
+		}
+
+		if !late.IsExpired(&license) || late.ValidateLicense(signedLicense).Valid {
+			t.Error("License should be expired for a clock past its expiry")
+		}
+	})
+
+	t.Run("Helpers", func(t *testing.T) {
+		at := licenser.WithClock(licenser.FixedClock(expiresAt.Add(-2 * time.Hour)))
+
+		if remaining := licenser.CalculateRemainingTime(license.ExpiresAt, at); remaining != 2*time.Hour {
+			t.Errorf("Expected 2h remaining, got %v", remaining)
+		}
+
+		if formatted := licenser.FormatTimeUntilExpiry(license.ExpiresAt, at); formatted != "2h" {
+			t.Errorf("Expected '2h', got '%s'", formatted)
+		}
+
+		if !licenser.IsExpiringSoon(&license, 3*time.Hour, at) || licenser.IsExpiringSoon(&license, time.Hour, at) {
+			t.Error("IsExpiringSoon should use the given clock")
+		}
+
+		if status := licenser.GetLicenseStatus(&license, at); status != licenser.StatusActive {
+			t.Errorf("Expected status '%s', got '%s'", licenser.StatusActive, status)
+		}
+
+		after := licenser.WithClock(licenser.FixedClock(expiresAt.Add(time.Hour)))
+		if status := licenser.GetLicenseStatus(&license, after); status != licenser.StatusExpired {
+			t.Errorf("Expected status '%s', got '%s'", licenser.StatusExpired, status)
+		}
+	})
+}
+
+func TestClockSkewAndGracePeriod(t *testing.T) {
+	notBefore := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	expiresAt := notBefore.AddDate(0, 6, 0)
+
+	issuer := newEd25519Manager(t, licenser.Config{Clock: licenser.FixedClock(notBefore.Add(-time.Hour))})
+
+	license := licenser.License{
+		Customer:  "Field Customer",
+		AppID:     "field-app",
+		Services:  []licenser.Service{{ID: "test", Name: "Test"}},
+		NotBefore: notBefore.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+		Version:   "1.0.0",
+	}
+
+	signedLicense, err := issuer.GenerateLicense(&license)
+	if err != nil {
+		t.Fatalf("Failed to generate license: %v", err)
+	}
+
+	newVerifier := func(t *testing.T, now time.Time) *licenser.Verifier {
+		t.Helper()
+
+		verifier, err := licenser.NewVerifier(licenser.Config{
+			PublicKeyPEM: exportPublicKey(t, issuer),
+			Clock:        licenser.FixedClock(now),
+			ClockSkew:    5 * time.Minute,
+			GracePeriod:  7 * 24 * time.Hour,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create verifier: %v", err)
+		}
+
+		return verifier
+	}
+
+	tests := []struct {
+		name   string
+		now    time.Time
+		valid  bool
+		status string
+	}{
+		{"BeforeSkew", notBefore.Add(-10 * time.Minute), false, licenser.StatusNotYetValid},
+		{"WithinSkewBeforeStart", notBefore.Add(-4 * time.Minute), true, licenser.StatusActive},
+		{"WithinSkewAfterExpiry", expiresAt.Add(4 * time.Minute), true, licenser.StatusActive},
+		{"Grace", expiresAt.Add(3 * 24 * time.Hour), true, licenser.StatusGrace},
+		{"AfterGrace", expiresAt.Add(8 * 24 * time.Hour), false, licenser.StatusExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := newVerifier(t, tt.now)
+
+			result := verifier.ValidateLicense(signedLicense)
+			if result.Valid != tt.valid {
+				t.Errorf("Expected valid %v, got %v (errors: %v)", tt.valid, result.Valid, result.Errors)
+			}
+
+			if inGrace := tt.status == licenser.StatusGrace; inGrace != (len(result.Warnings) > 0) {
+				t.Errorf("Expected a grace warning only in the grace period, got %v", result.Warnings)
+			}
+
+			info := verifier.GetLicenseInfo(&license)
+			if info.Status != tt.status {
+				t.Errorf("Expected status '%s', got '%s'", tt.status, info.Status)
+			}
+
+			if expired := info.TimeUntilExpiry == licenser.LicenseExpired; expired != (tt.status == licenser.StatusGrace ||
+				tt.status == licenser.StatusExpired) {
+				t.Errorf("Time until expiry '%s' does not match status '%s'", info.TimeUntilExpiry, info.Status)
+			}
+
+			if verifier.InGracePeriod(&license) != (tt.status == licenser.StatusGrace) {
+				t.Errorf("Unexpected InGracePeriod for status '%s'", tt.status)
+			}
+
+			if status := licenser.GetLicenseStatus(&license,
+				licenser.WithClock(licenser.FixedClock(tt.now)),
+				licenser.WithClockSkew(5*time.Minute),
+				licenser.WithGracePeriod(7*24*time.Hour)); status != tt.status {
+				t.Errorf("Expected helper status '%s', got '%s'", tt.status, status)
+			}
+		})
+	}
+
+	t.Run("GraceInfo", func(t *testing.T) {
+		verifier := newVerifier(t, expiresAt.Add(24*time.Hour))
+
+		info := verifier.GetLicenseInfo(&license)
+		if info.GraceEndsAt == nil || !info.GraceEndsAt.Equal(expiresAt.Add(7*24*time.Hour+5*time.Minute)) {
+			t.Errorf("Unexpected grace end %v", info.GraceEndsAt)
+		}
+
+		if !verifier.IsActive(&license) || verifier.IsExpired(&license) || verifier.CheckExpiration(&license) != nil {
+			t.Error("License in its grace period should be active")
+		}
+	})
+
+	t.Run("ExpiryWarningWithinSkew", func(t *testing.T) {
+		verifier, err := licenser.NewVerifier(licenser.Config{
+			PublicKeyPEM:        exportPublicKey(t, issuer),
+			Clock:               licenser.FixedClock(expiresAt.Add(4 * time.Minute)),
+			ClockSkew:           5 * time.Minute,
+			ExpiryWarningWindow: 30 * 24 * time.Hour,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create verifier: %v", err)
+		}
+
+		result := verifier.ValidateLicense(signedLicense)
+		if !result.Valid || len(result.Warnings) != 1 {
+			t.Fatalf("Expected one warning, got errors %v and warnings %v", result.Errors, result.Warnings)
+		}
+
+		if warning := result.Warnings[0]; !contains(warning, "accepted within clock skew") ||
+			contains(warning, licenser.LicenseExpired) {
+			t.Errorf("Unexpected warning '%s'", result.Warnings[0])
+		}
+	})
+
+	t.Run("NoLeeway", func(t *testing.T) {
+		// Without ClockSkew or GracePeriod a license fails right after expiry.
+		if result := issuer.ValidateLicenseAt(signedLicense, expiresAt.Add(time.Second)); result.Valid {
+			t.Error("License should be expired without leeway")
+		}
+	})
+}
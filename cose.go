@@ -0,0 +1,434 @@
+/*******************************************************************
+
+		::          ::        +--------+-----------------------+
+		  ::      ::          | Author | Dmitry Novikov        |
+		::::::::::::::        | Email  | dredfort.42@gmail.com |
+	  ::::  ::::::  ::::      +--------+-----------------------+
+	::::::::::::::::::::::
+	::  ::::::::::::::  ::    File     | cose.go
+	::  ::          ::  ::    Created  | 2026-10-16
+		  ::::  ::::          Modified | 2026-10-16
+
+	GitHub:   https://github.com/dredfort42
+	LinkedIn: https://linkedin.com/in/novikov-da
+
+*******************************************************************/
+
+package licenser
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// COSE_Sign1 (RFC 9052) constants.
+const (
+	coseSign1Tag     = 18
+	coseSign1Context = "Signature1"
+	coseHeaderAlg    = 1
+	coseHeaderCrit   = 2
+	coseHeaderKeyID  = 4
+)
+
+// coseAlgorithms maps algorithms to their COSE identifiers (RFC 9053, RFC 8812).
+var coseAlgorithms = map[string]int64{
+	AlgorithmRS256: -257,
+	AlgorithmPS256: -37,
+	AlgorithmEdDSA: -8,
+	AlgorithmES256: -7,
+	AlgorithmES384: -35,
+}
+
+// cwtClaims maps License fields to the CWT claim keys (RFC 8392) they are
+// carried in, mirroring the JWT claims used by license tokens.
+var cwtClaims = map[string]int64{
+	"customer":   2, // sub
+	"app_id":     3, // aud
+	"expires_at": 4, // exp
+	"not_before": 5, // nbf
+	"issued_at":  6, // iat
+}
+
+// generateCOSE signs license as a COSE_Sign1 message with a CBOR claims payload.
+func (i *Issuer) generateCOSE(license *License) (*SignedLicense, error) {
+	algorithm, ok := coseAlgorithms[i.signer.Algorithm()]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s has no COSE identifier", ErrUnsupportedAlgorithm, i.signer.Algorithm())
+	}
+
+	payload, err := encodeCOSEClaims(license)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal license: %w", err)
+	}
+
+	header := map[any]any{coseHeaderAlg: int64(algorithm)}
+	if i.keyID != "" {
+		header[coseHeaderKeyID] = []byte(i.keyID)
+	}
+
+	protected, err := cborMarshal(header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal COSE header: %w", err)
+	}
+
+	toBeSigned, err := coseSigStructure(protected, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := i.signData(toBeSigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign license: %w", err)
+	}
+
+	// Data mirrors the payload exactly, e.g. extension numbers as CBOR reads them.
+	decoded, err := decodeCOSEClaims(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SignedLicense{
+		Data:      *decoded,
+		Format:    FormatCOSE,
+		Protected: base64.StdEncoding.EncodeToString(protected),
+		Payload:   base64.StdEncoding.EncodeToString(payload),
+		Signature: signature,
+		KeyID:     i.keyID,
+		Algorithm: i.signer.Algorithm(),
+		CreatedAt: i.config.Clock.Now().Unix(),
+	}, nil
+}
+
+// EncodeCOSE returns a COSE-format signed license as a tagged COSE_Sign1
+// message. Licenses in other formats are signed over different bytes and
+// have to be issued again with Config.FileFormat set to FileFormatCOSE.
+func EncodeCOSE(signedLicense *SignedLicense) ([]byte, error) {
+	if signedLicense.Format != FormatCOSE {
+		return nil, fmt.Errorf("%w: %q cannot be encoded as COSE", ErrUnsupportedFormat, signedLicense.Format)
+	}
+
+	protected, err := base64.StdEncoding.DecodeString(signedLicense.Protected)
+	if err != nil {
+		return nil, fmt.Errorf("%w: protected header is not valid base64", ErrInvalidPayload)
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(signedLicense.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: payload is not valid base64", ErrInvalidPayload)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(signedLicense.Signature)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	return cborMarshal(cborTagged{
+		Number:  coseSign1Tag,
+		Content: []any{protected, map[any]any{}, payload, signature},
+	})
+}
+
+// DecodeCOSE decodes a tagged or untagged COSE_Sign1 message without
+// validating it. The key ID may be carried in either header bucket.
+func DecodeCOSE(data []byte) (*SignedLicense, error) {
+	message, err := cborUnmarshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCOSE, err)
+	}
+
+	if tagged, ok := message.(cborTagged); ok {
+		if tagged.Number != coseSign1Tag {
+			return nil, fmt.Errorf("%w: unexpected tag %d", ErrInvalidCOSE, tagged.Number)
+		}
+
+		message = tagged.Content
+	}
+
+	items, ok := message.([]any)
+	if !ok || len(items) != 4 {
+		return nil, fmt.Errorf("%w: expected a 4-element array", ErrInvalidCOSE)
+	}
+
+	protected, ok1 := items[0].([]byte)
+	unprotected, ok2 := items[1].(map[any]any)
+	payload, ok3 := items[2].([]byte)
+	signature, ok4 := items[3].([]byte)
+
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		return nil, fmt.Errorf("%w: unexpected element types (detached payloads are not supported)", ErrInvalidCOSE)
+	}
+
+	algorithm, keyID, err := decodeCOSEHeader(protected)
+	if err != nil {
+		return nil, err
+	}
+
+	if kid, ok := unprotected[int64(coseHeaderKeyID)].([]byte); ok && keyID == "" {
+		keyID = string(kid)
+	}
+
+	license, err := decodeCOSEClaims(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SignedLicense{
+		Data:      *license,
+		Format:    FormatCOSE,
+		Protected: base64.StdEncoding.EncodeToString(protected),
+		Payload:   base64.StdEncoding.EncodeToString(payload),
+		Signature: base64.StdEncoding.EncodeToString(signature),
+		KeyID:     keyID,
+		Algorithm: algorithm,
+		CreatedAt: license.IssuedAt,
+	}, nil
+}
+
+// decodeCOSEPayload returns the Sig_structure of a COSE-format license. The
+// algorithm and a protected key ID must match the license fields.
+func decodeCOSEPayload(signedLicense *SignedLicense) ([]byte, *License, error) {
+	protected, err := base64.StdEncoding.DecodeString(signedLicense.Protected)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: protected header is not valid base64", ErrInvalidPayload)
+	}
+
+	algorithm, keyID, err := decodeCOSEHeader(protected)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if algorithm != signedLicense.Algorithm || (keyID != "" && keyID != signedLicense.KeyID) {
+		return nil, nil, fmt.Errorf("%w: algorithm or key ID differs from the protected header", ErrPayloadMismatch)
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(signedLicense.Payload)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: payload is not valid base64", ErrInvalidPayload)
+	}
+
+	license, err := decodeCOSEClaims(payload)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	toBeSigned, err := coseSigStructure(protected, payload)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return toBeSigned, license, nil
+}
+
+// coseSigStructure builds the bytes a COSE_Sign1 signature covers.
+func coseSigStructure(protected, payload []byte) ([]byte, error) {
+	return cborMarshal([]any{coseSign1Context, protected, []byte{}, payload})
+}
+
+// decodeCOSEHeader returns the algorithm and key ID of a protected header.
+func decodeCOSEHeader(protected []byte) (string, string, error) {
+	if len(protected) == 0 {
+		return "", "", fmt.Errorf("%w: missing protected header", ErrInvalidCOSE)
+	}
+
+	decoded, err := cborUnmarshal(protected)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: protected header: %w", ErrInvalidCOSE, err)
+	}
+
+	header, ok := decoded.(map[any]any)
+	if !ok {
+		return "", "", fmt.Errorf("%w: protected header is not a map", ErrInvalidCOSE)
+	}
+
+	// No header extensions are understood, so any critical one must be rejected.
+	if _, ok := header[int64(coseHeaderCrit)]; ok {
+		return "", "", fmt.Errorf("%w: unsupported critical headers", ErrInvalidCOSE)
+	}
+
+	var algorithm string
+
+	if id, ok := header[int64(coseHeaderAlg)].(int64); ok {
+		for name, coseID := range coseAlgorithms {
+			if coseID == id {
+				algorithm = name
+			}
+		}
+	}
+
+	if algorithm == "" {
+		return "", "", fmt.Errorf("%w: unsupported algorithm %v", ErrInvalidCOSE, header[int64(coseHeaderAlg)])
+	}
+
+	var keyID string
+	if kid, ok := header[int64(coseHeaderKeyID)].([]byte); ok {
+		keyID = string(kid)
+	}
+
+	return algorithm, keyID, nil
+}
+
+// encodeCOSEClaims encodes license as a CBOR claims map.
+func encodeCOSEClaims(license *License) ([]byte, error) {
+	data, err := json.Marshal(license)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var fields map[string]any
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, err
+	}
+
+	claims := make(map[any]any, len(fields))
+
+	for name, value := range fields {
+		if key, ok := cwtClaims[name]; ok {
+			claims[key] = jsonToCBOR(value)
+		} else {
+			claims[name] = jsonToCBOR(value)
+		}
+	}
+
+	return cborMarshal(claims)
+}
+
+// decodeCOSEClaims maps a CBOR claims map back to a license. Unknown claims,
+// including unknown integer keys, end up in Extensions.
+func decodeCOSEClaims(payload []byte) (*License, error) {
+	decoded, err := cborUnmarshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPayload, err)
+	}
+
+	claims, ok := decoded.(map[any]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: claims are not a map", ErrInvalidPayload)
+	}
+
+	fields := make(map[string]any, len(claims))
+
+	for key, value := range claims {
+		name := fmt.Sprint(key)
+
+		if _, ok := cwtClaims[name]; ok {
+			return nil, fmt.Errorf("%w: license field %q must be sent as a CWT claim", ErrInvalidPayload, name)
+		}
+
+		for field, claim := range cwtClaims {
+			if key == claim {
+				name = field
+			}
+		}
+
+		converted, err := cborToJSON(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: claim %s: %w", ErrInvalidPayload, name, err)
+		}
+
+		if _, ok := fields[name]; ok {
+			return nil, fmt.Errorf("%w: duplicate claim %s", ErrInvalidPayload, name)
+		}
+
+		fields[name] = converted
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPayload, err)
+	}
+
+	var license License
+	if err := json.Unmarshal(data, &license); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPayload, err)
+	}
+
+	return &license, nil
+}
+
+// jsonToCBOR converts a value decoded with json.Decoder.UseNumber for cborMarshal.
+func jsonToCBOR(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+
+		f, _ := v.Float64()
+
+		return f
+	case map[string]any:
+		m := make(map[any]any, len(v))
+		for key, value := range v {
+			m[key] = jsonToCBOR(value)
+		}
+
+		return m
+	case []any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = jsonToCBOR(item)
+		}
+
+		return items
+	default:
+		return v
+	}
+}
+
+// cborToJSON converts a value decoded by cborUnmarshal for json.Marshal.
+// Byte strings become base64 strings and tags are dropped.
+func cborToJSON(v any) (any, error) {
+	switch v := v.(type) {
+	case map[any]any:
+		m := make(map[string]any, len(v))
+
+		for key, value := range v {
+			name := fmt.Sprint(key)
+			if _, ok := m[name]; ok {
+				return nil, fmt.Errorf("duplicate key %s", name)
+			}
+
+			converted, err := cborToJSON(value)
+			if err != nil {
+				return nil, err
+			}
+
+			m[name] = converted
+		}
+
+		return m, nil
+	case []any:
+		items := make([]any, len(v))
+
+		for i, item := range v {
+			converted, err := cborToJSON(item)
+			if err != nil {
+				return nil, err
+			}
+
+			items[i] = converted
+		}
+
+		return items, nil
+	case cborTagged:
+		return cborToJSON(v.Content)
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("unsupported float %v", v)
+		}
+
+		return v, nil
+	case int64:
+		// Keep integers exact; float64 would lose precision above 2^53.
+		return json.Number(strconv.FormatInt(v, 10)), nil
+	default:
+		return v, nil
+	}
+}
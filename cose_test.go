@@ -0,0 +1,311 @@
+/*******************************************************************
+
+		::          ::        +--------+-----------------------+
+		  ::      ::          | Author | Dmitry Novikov        |
+		::::::::::::::        | Email  | dredfort.42@gmail.com |
+	  ::::  ::::::  ::::      +--------+-----------------------+
+	::::::::::::::::::::::
+	::  ::::::::::::::  ::    File     | cose_test.go
+	::  ::          ::  ::    Created  | 2026-10-16
+		  ::::  ::::          Modified | 2026-10-16
+
+	GitHub:   https://github.com/dredfort42
+	LinkedIn: https://linkedin.com/in/novikov-da
+
+*******************************************************************/
+
+package licenser_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	licenser "github.com/dredfort42/go_licenser"
+)
+
+// cborItem hand-encodes a CBOR head followed by content, independently of
+// the package encoder, for major types with lengths below 256.
+func cborItem(major byte, n int, content []byte) []byte {
+	var head []byte
+	if n < 24 {
+		head = []byte{major<<5 | byte(n)}
+	} else {
+		head = []byte{major<<5 | 24, byte(n)}
+	}
+
+	return append(head, content...)
+}
+
+func cborText(s string) []byte { return cborItem(3, len(s), []byte(s)) }
+
+func cborBytes(b []byte) []byte { return cborItem(2, len(b), b) }
+
+// sigStructure hand-encodes the RFC 9052 Sig_structure of a COSE_Sign1 message.
+func sigStructure(protected, payload []byte) []byte {
+	toBeSigned := []byte{0x84}
+	toBeSigned = append(toBeSigned, cborText("Signature1")...)
+	toBeSigned = append(toBeSigned, cborBytes(protected)...)
+	toBeSigned = append(toBeSigned, 0x40)
+
+	return append(toBeSigned, cborBytes(payload)...)
+}
+
+// decodeCOSEParts decodes the protected header, payload and signature of a
+// COSE-format license.
+func decodeCOSEParts(t *testing.T, signedLicense *licenser.SignedLicense) ([]byte, []byte, []byte) {
+	t.Helper()
+
+	protected, err := base64.StdEncoding.DecodeString(signedLicense.Protected)
+	if err != nil {
+		t.Fatalf("Failed to decode protected header: %v", err)
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(signedLicense.Payload)
+	if err != nil {
+		t.Fatalf("Failed to decode payload: %v", err)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(signedLicense.Signature)
+	if err != nil {
+		t.Fatalf("Failed to decode signature: %v", err)
+	}
+
+	return protected, payload, signature
+}
+
+func TestCOSELicense(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	signer, err := licenser.NewSigner(key, licenser.AlgorithmEdDSA)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+
+	manager, err := licenser.NewManager(licenser.Config{
+		GeneratorMode: true,
+		Signer:        signer,
+		FileFormat:    licenser.FileFormatCOSE,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	license := licenser.License{
+		Customer:   "COSE Customer",
+		AppID:      "cose-app",
+		Services:   []licenser.Service{{ID: "test", Name: "Test"}},
+		Limits:     map[string]int{"devices": 3},
+		Extensions: map[string]json.RawMessage{"ratio": json.RawMessage(`1.50`)},
+	}
+
+	signedLicense, err := manager.GenerateLicense(&license)
+	if err != nil {
+		t.Fatalf("Failed to generate license: %v", err)
+	}
+
+	dir := t.TempDir()
+	licensePath := filepath.Join(dir, "license.cose")
+
+	if err := manager.SaveLicense(signedLicense, licensePath); err != nil {
+		t.Fatalf("Failed to save license: %v", err)
+	}
+
+	t.Run("SaveAndLoad", func(t *testing.T) {
+		loaded, result, err := manager.LoadAndValidateLicense(licensePath)
+		if err != nil {
+			t.Fatalf("Failed to load license: %v", err)
+		}
+
+		if !result.Valid {
+			t.Errorf("COSE license should be valid, errors: %v", result.Errors)
+		}
+
+		if loaded.Data.Customer != license.Customer || loaded.Data.Limits["devices"] != 3 {
+			t.Errorf("Expected license data to be decoded, got %+v", loaded.Data)
+		}
+
+		if result := manager.ValidateLicense(signedLicense); !result.Valid {
+			t.Errorf("Generated COSE license should be valid, errors: %v", result.Errors)
+		}
+	})
+
+	t.Run("Smaller", func(t *testing.T) {
+		coseData, err := os.ReadFile(licensePath)
+		if err != nil {
+			t.Fatalf("Failed to read license: %v", err)
+		}
+
+		jsonManager := newEd25519Manager(t, licenser.Config{})
+		jsonPath := filepath.Join(dir, "license.json")
+
+		jsonLicense, err := jsonManager.GenerateLicense(&license)
+		if err != nil {
+			t.Fatalf("Failed to generate license: %v", err)
+		}
+
+		if err := jsonManager.SaveLicense(jsonLicense, jsonPath); err != nil {
+			t.Fatalf("Failed to save license: %v", err)
+		}
+
+		jsonData, err := os.ReadFile(jsonPath)
+		if err != nil {
+			t.Fatalf("Failed to read license: %v", err)
+		}
+
+		if len(coseData)*2 > len(jsonData) {
+			t.Errorf("Expected COSE license to be much smaller than JSON, got %d and %d bytes",
+				len(coseData), len(jsonData))
+		}
+	})
+
+	t.Run("SigStructure", func(t *testing.T) {
+		protected, payload, signature := decodeCOSEParts(t, signedLicense)
+
+		// {1: -8 (EdDSA), 4: h'<kid>'}
+		expected := append([]byte{0xa2, 0x01, 0x27, 0x04}, cborBytes([]byte(manager.KeyID()))...)
+		if !bytes.Equal(protected, expected) {
+			t.Errorf("Expected protected header %x, got %x", expected, protected)
+		}
+
+		if !ed25519.Verify(key.Public().(ed25519.PublicKey), sigStructure(protected, payload), signature) {
+			t.Error("Signature should cover the RFC 9052 Sig_structure")
+		}
+	})
+
+	t.Run("RS256", func(t *testing.T) {
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatalf("Failed to generate key: %v", err)
+		}
+
+		rsaSigner, err := licenser.NewSigner(rsaKey, licenser.AlgorithmRS256)
+		if err != nil {
+			t.Fatalf("Failed to create signer: %v", err)
+		}
+
+		rsaManager, err := licenser.NewManager(licenser.Config{
+			GeneratorMode: true,
+			Signer:        rsaSigner,
+			FileFormat:    licenser.FileFormatCOSE,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create manager: %v", err)
+		}
+
+		rsaLicense, err := rsaManager.GenerateLicense(&license)
+		if err != nil {
+			t.Fatalf("Failed to generate license: %v", err)
+		}
+
+		// Other COSE libraries verify alg -257 as standard RS256.
+		protected, payload, signature := decodeCOSEParts(t, rsaLicense)
+		hash := sha256.Sum256(sigStructure(protected, payload))
+
+		if err := rsa.VerifyPKCS1v15(&rsaKey.PublicKey, crypto.SHA256, hash[:], signature); err != nil {
+			t.Errorf("RS256 COSE license should verify with crypto.SHA256: %v", err)
+		}
+	})
+
+	t.Run("ForeignMessage", func(t *testing.T) {
+		service := append([]byte{0xa2}, cborText("id")...)
+		service = append(service, cborText("test")...)
+		service = append(service, cborText("name")...)
+		service = append(service, cborText("Test")...)
+
+		payload := []byte{0xa5, 0x01}
+		payload = append(payload, cborText("billing")...)
+		payload = append(payload, 0x02)
+		payload = append(payload, cborText("Foreign Customer")...)
+		payload = append(payload, 0x03)
+		payload = append(payload, cborText("foreign-app")...)
+		payload = append(payload, 0x06, 0x1a, 0x68, 0xe7, 0x78, 0x00)
+		payload = append(payload, cborText("services")...)
+		payload = append(payload, 0x81)
+		payload = append(payload, service...)
+
+		// Only the algorithm is protected; the key ID travels unprotected.
+		protected := []byte{0xa1, 0x01, 0x27}
+
+		toBeSigned := []byte{0x84}
+		toBeSigned = append(toBeSigned, cborText("Signature1")...)
+		toBeSigned = append(toBeSigned, cborBytes(protected)...)
+		toBeSigned = append(toBeSigned, 0x40)
+		toBeSigned = append(toBeSigned, cborBytes(payload)...)
+
+		message := []byte{0xd2, 0x84}
+		message = append(message, cborBytes(protected)...)
+		message = append(message, 0xa1, 0x04)
+		message = append(message, cborBytes([]byte(manager.KeyID()))...)
+		message = append(message, cborBytes(payload)...)
+		message = append(message, cborBytes(ed25519.Sign(key, toBeSigned))...)
+
+		foreignPath := filepath.Join(dir, "foreign.cose")
+		if err := os.WriteFile(foreignPath, message, 0600); err != nil {
+			t.Fatalf("Failed to write license: %v", err)
+		}
+
+		loaded, result, err := manager.LoadAndValidateLicense(foreignPath)
+		if err != nil {
+			t.Fatalf("Failed to load license: %v", err)
+		}
+
+		if !result.Valid {
+			t.Errorf("Foreign COSE license should be valid, errors: %v", result.Errors)
+		}
+
+		if loaded.Data.IssuedAt != 1760000000 || loaded.Data.AppID != "foreign-app" {
+			t.Errorf("Expected CWT claims to be mapped, got %+v", loaded.Data)
+		}
+
+		if string(loaded.Data.Extensions["1"]) != `"billing"` {
+			t.Errorf("Expected unknown claim 1 in extensions, got %v", loaded.Data.Extensions)
+		}
+	})
+
+	t.Run("Tampered", func(t *testing.T) {
+		tampered := *signedLicense
+		tampered.Data.Limits = map[string]int{"devices": 300}
+
+		if manager.ValidateLicense(&tampered).Valid {
+			t.Error("Tampered COSE license should be invalid")
+		}
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		for name, data := range map[string][]byte{
+			"Truncated":  {0xd2, 0x84, 0x43},
+			"WrongTag":   {0xd1, 0x84, 0x40, 0xa0, 0x40, 0x40},
+			"HugeLength": {0x84, 0x5b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+			"NoHeader":   {0x84, 0x40, 0xa0, 0x41, 0xa0, 0x40},
+		} {
+			t.Run(name, func(t *testing.T) {
+				if _, err := licenser.DecodeCOSE(data); !errors.Is(err, licenser.ErrInvalidCOSE) {
+					t.Errorf("Expected ErrInvalidCOSE, got %v", err)
+				}
+			})
+		}
+	})
+
+	t.Run("SaveJSONLicenseAsCOSE", func(t *testing.T) {
+		jsonLicense := *signedLicense
+		jsonLicense.Format = ""
+
+		err := manager.SaveLicense(&jsonLicense, filepath.Join(dir, "json.cose"))
+		if !errors.Is(err, licenser.ErrUnsupportedFormat) {
+			t.Errorf("Expected ErrUnsupportedFormat, got %v", err)
+		}
+	})
+}
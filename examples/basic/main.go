@@ -7,7 +7,7 @@
 	::::::::::::::::::::::
 	::  ::::::::::::::  ::    File     | main.go
 	::  ::          ::  ::    Created  | 2025-08-08
-		  ::::  ::::          Modified | 2025-08-08
+		  ::::  ::::          Modified | 2026-10-16
 
 	GitHub:   https://github.com/dredfort42
 	LinkedIn: https://linkedin.com/in/novikov-da
@@ -30,8 +30,9 @@ func main() {
 	// Step 1: Create a License Manager
 	fmt.Println("1. Creating License Manager...")
 	config := licenser.Config{
-		KeySize:       2048,
-		GeneratorMode: true,
+		KeySize:             2048,
+		GeneratorMode:       true,
+		OverwritePrivateKey: true, // Each run generates a fresh key pair
 	}
 
 	manager, err := licenser.NewManager(config)
@@ -7,7 +7,7 @@
 	::::::::::::::::::::::
 	::  ::::::::::::::  ::    File     | main.go
 	::  ::          ::  ::    Created  | 2025-08-08
-		  ::::  ::::          Modified | 2025-08-08
+		  ::::  ::::          Modified | 2026-10-16
 
 	GitHub:   https://github.com/dredfort42
 	LinkedIn: https://linkedin.com/in/novikov-da
@@ -26,23 +26,23 @@ import (
 func main() {
 	fmt.Println("=== License Validation Example ===")
 
-	// Step 1: Create a license manager with only the public key
-	fmt.Println("1. Creating License Manager for validation...")
+	// Step 1: Create a license verifier with only the public key
+	fmt.Println("1. Creating License Verifier...")
 	config := licenser.Config{
 		PublicKeyPath: "../basic/examples/keys/public.pem",
 	}
 
-	manager, err := licenser.NewManager(config)
+	verifier, err := licenser.NewVerifier(config)
 	if err != nil {
-		log.Fatal("Failed to create license manager:", err)
+		log.Fatal("Failed to create license verifier:", err)
 	}
-	fmt.Println("✓ License Manager created for validation")
+	fmt.Println("✓ License Verifier created")
 
 	// Step 2: Load and validate an existing license
 	fmt.Println("\n2. Loading and validating license...")
 	licensePath := "../basic/examples/licenses/acme-corp.json"
 
-	signedLicense, result, err := manager.LoadAndValidateLicense(licensePath)
+	signedLicense, result, err := verifier.LoadAndValidateLicense(licensePath)
 	if err != nil {
 		log.Fatal("Failed to load or validate license:", err)
 	}
@@ -55,7 +55,7 @@ func main() {
 
 	// Step 3: Display key information from the validated license
 	fmt.Println("\n3. Displaying Validated License Information:")
-	info := manager.GetLicenseInfo(&signedLicense.Data)
+	info := verifier.GetLicenseInfo(&signedLicense.Data)
 	fmt.Printf("   Customer: %s\n", info.Customer)
 	fmt.Printf("   App ID: %s\n", info.AppID)
 	fmt.Printf("   Status: %s\n", info.Status)
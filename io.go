@@ -0,0 +1,237 @@
+/*******************************************************************
+
+		::          ::        +--------+-----------------------+
+		  ::      ::          | Author | Dmitry Novikov        |
+		::::::::::::::        | Email  | dredfort.42@gmail.com |
+	  ::::  ::::::  ::::      +--------+-----------------------+
+	::::::::::::::::::::::
+	::  ::::::::::::::  ::    File     | io.go
+	::  ::          ::  ::    Created  | 2026-10-16
+		  ::::  ::::          Modified | 2026-10-16
+
+	GitHub:   https://github.com/dredfort42
+	LinkedIn: https://linkedin.com/in/novikov-da
+
+*******************************************************************/
+
+package licenser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// maxLicenseSize bounds how much ReadLicense reads from an untrusted source.
+const maxLicenseSize = 4 << 20
+
+// ReadLicense reads a license from r. JSON, PEM-armored and COSE licenses are
+// detected automatically.
+func (m *Manager) ReadLicense(r io.Reader) (*SignedLicense, error) {
+	return m.verifier.ReadLicense(r)
+}
+
+// ReadLicense reads a license from r. JSON, PEM-armored and COSE licenses are
+// detected automatically.
+func (v *Verifier) ReadLicense(r io.Reader) (*SignedLicense, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxLicenseSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read license: %w", err)
+	}
+
+	if len(data) > maxLicenseSize {
+		return nil, fmt.Errorf("failed to read license: larger than %d bytes", maxLicenseSize)
+	}
+
+	return decodeLicense(data)
+}
+
+// LoadLicenseFS loads a license from a file system such as an embed.FS.
+func (m *Manager) LoadLicenseFS(fsys fs.FS, name string) (*SignedLicense, error) {
+	return m.verifier.LoadLicenseFS(fsys, name)
+}
+
+// LoadLicenseFS loads a license from a file system such as an embed.FS.
+func (v *Verifier) LoadLicenseFS(fsys fs.FS, name string) (*SignedLicense, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read license file: %w", err)
+	}
+	defer file.Close()
+
+	return v.ReadLicense(file)
+}
+
+// WriteLicense writes a license to w in the encoding selected by Config.FileFormat.
+func (m *Manager) WriteLicense(w io.Writer, signedLicense *SignedLicense) error {
+	return writeLicense(w, signedLicense, m.config.FileFormat)
+}
+
+// WriteLicense writes a license to w in the encoding selected by Config.FileFormat.
+func (i *Issuer) WriteLicense(w io.Writer, signedLicense *SignedLicense) error {
+	return writeLicense(w, signedLicense, i.config.FileFormat)
+}
+
+func writeLicense(w io.Writer, signedLicense *SignedLicense, fileFormat string) error {
+	data, err := encodeLicense(signedLicense, fileFormat)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write license: %w", err)
+	}
+
+	return nil
+}
+
+// WritePrivateKey writes the private key to w as PEM, encrypted when a
+// passphrase is configured.
+func (m *Manager) WritePrivateKey(w io.Writer) error {
+	if m.issuer == nil {
+		return fmt.Errorf("failed to export private key: %w", errValidatorMode)
+	}
+
+	return m.issuer.WritePrivateKey(w)
+}
+
+// WritePrivateKey writes the private key to w as PEM, encrypted when a
+// passphrase is configured.
+func (i *Issuer) WritePrivateKey(w io.Writer) error {
+	privateKeyPEM, err := i.ExportPrivateKey()
+	if err != nil {
+		return fmt.Errorf("failed to export private key: %w", err)
+	}
+
+	_, err = io.WriteString(w, privateKeyPEM)
+
+	return err
+}
+
+// WritePublicKey writes the public key to w as PEM.
+func (m *Manager) WritePublicKey(w io.Writer) error {
+	return m.verifier.WritePublicKey(w)
+}
+
+// WritePublicKey writes the public half of the signing key to w as PEM.
+func (i *Issuer) WritePublicKey(w io.Writer) error {
+	publicKeyPEM, err := i.ExportPublicKey()
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, publicKeyPEM)
+
+	return err
+}
+
+// WritePublicKey writes the public key to w as PEM.
+func (v *Verifier) WritePublicKey(w io.Writer) error {
+	publicKeyPEM, err := v.ExportPublicKey()
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, publicKeyPEM)
+
+	return err
+}
+
+// readFile reads a file from fsys, or from the operating system when fsys is nil.
+func readFile(fsys fs.FS, name string) ([]byte, error) {
+	if fsys != nil {
+		return fs.ReadFile(fsys, name)
+	}
+
+	// #nosec G304
+	return os.ReadFile(name)
+}
+
+// writeFile atomically replaces name with the output of write. The data is
+// written to a temporary file in the same directory, synced, given perm and
+// renamed into place, so readers never observe a partial file. Unless
+// overwrite is set, an existing file is left untouched and fs.ErrExist is
+// returned.
+func writeFile(name string, perm os.FileMode, overwrite bool, write func(io.Writer) error) (err error) {
+	dir, base := filepath.Split(name)
+	if dir == "" {
+		dir = "."
+	}
+
+	if !overwrite {
+		if _, err := os.Lstat(name); err == nil {
+			return &fs.PathError{Op: "write", Path: name, Err: fs.ErrExist}
+		}
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+base+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	if err = write(tmp); err != nil {
+		return err
+	}
+
+	if err = tmp.Chmod(perm); err != nil {
+		return err
+	}
+
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	if err = commitFile(tmp.Name(), name, overwrite); err != nil {
+		return err
+	}
+
+	syncDir(dir)
+
+	return nil
+}
+
+// commitFile moves the temporary file to name. Without overwrite a hard link
+// is used, which fails if name appeared since the existence check; file
+// systems without hard links fall back to a rename.
+func commitFile(tmpName, name string, overwrite bool) error {
+	if overwrite {
+		return os.Rename(tmpName, name)
+	}
+
+	err := os.Link(tmpName, name)
+	if err == nil {
+		return os.Remove(tmpName)
+	}
+
+	if errors.Is(err, fs.ErrExist) {
+		return err
+	}
+
+	return os.Rename(tmpName, name)
+}
+
+// syncDir makes a rename in dir durable. Errors are ignored because not every
+// platform can sync directories.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+
+	_ = d.Sync()
+	_ = d.Close()
+}
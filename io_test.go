@@ -0,0 +1,277 @@
+/*******************************************************************
+
+		::          ::        +--------+-----------------------+
+		  ::      ::          | Author | Dmitry Novikov        |
+		::::::::::::::        | Email  | dredfort.42@gmail.com |
+	  ::::  ::::::  ::::      +--------+-----------------------+
+	::::::::::::::::::::::
+	::  ::::::::::::::  ::    File     | io_test.go
+	::  ::          ::  ::    Created  | 2026-10-16
+		  ::::  ::::          Modified | 2026-10-16
+
+	GitHub:   https://github.com/dredfort42
+	LinkedIn: https://linkedin.com/in/novikov-da
+
+*******************************************************************/
+
+package licenser_test
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	licenser "github.com/dredfort42/go_licenser"
+)
+
+func TestLicenseIO(t *testing.T) {
+	manager := newEd25519Manager(t, licenser.Config{})
+
+	license := licenser.License{
+		Customer: "Stream Customer",
+		AppID:    "stream-app",
+		Services: []licenser.Service{{ID: "test", Name: "Test"}},
+	}
+
+	signedLicense, err := manager.GenerateLicense(&license)
+	if err != nil {
+		t.Fatalf("Failed to generate license: %v", err)
+	}
+
+	var licenseBuf bytes.Buffer
+	if err := manager.WriteLicense(&licenseBuf, signedLicense); err != nil {
+		t.Fatalf("Failed to write license: %v", err)
+	}
+
+	var publicKeyBuf bytes.Buffer
+	if err := manager.WritePublicKey(&publicKeyBuf); err != nil {
+		t.Fatalf("Failed to write public key: %v", err)
+	}
+
+	t.Run("ReaderWriter", func(t *testing.T) {
+		loaded, err := manager.ReadLicense(bytes.NewReader(licenseBuf.Bytes()))
+		if err != nil {
+			t.Fatalf("Failed to read license: %v", err)
+		}
+
+		if result := manager.ValidateLicense(loaded); !result.Valid {
+			t.Errorf("License read from a stream should be valid, errors: %v", result.Errors)
+		}
+	})
+
+	t.Run("FS", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"keys/public.pem":      {Data: publicKeyBuf.Bytes()},
+			"licenses/default.lic": {Data: licenseBuf.Bytes()},
+		}
+
+		validator, err := licenser.NewManager(licenser.Config{FS: fsys, PublicKeyPath: "keys/public.pem"})
+		if err != nil {
+			t.Fatalf("Failed to create validator: %v", err)
+		}
+
+		loaded, err := validator.LoadLicenseFS(fsys, "licenses/default.lic")
+		if err != nil {
+			t.Fatalf("Failed to load license: %v", err)
+		}
+
+		if result := validator.ValidateLicense(loaded); !result.Valid {
+			t.Errorf("License loaded from FS should be valid, errors: %v", result.Errors)
+		}
+
+		if _, err := validator.LoadLicenseFS(fsys, "licenses/missing.lic"); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Expected fs.ErrNotExist, got %v", err)
+		}
+	})
+
+	t.Run("PrivateKeyFS", func(t *testing.T) {
+		var privateKeyBuf bytes.Buffer
+		if err := manager.WritePrivateKey(&privateKeyBuf); err != nil {
+			t.Fatalf("Failed to write private key: %v", err)
+		}
+
+		generator, err := licenser.NewManager(licenser.Config{
+			GeneratorMode:  true,
+			FS:             fstest.MapFS{"private.pem": {Data: privateKeyBuf.Bytes()}},
+			PrivateKeyPath: "private.pem",
+		})
+		if err != nil {
+			t.Fatalf("Failed to create generator: %v", err)
+		}
+
+		if generator.KeyID() != manager.KeyID() {
+			t.Errorf("Expected key ID '%s', got '%s'", manager.KeyID(), generator.KeyID())
+		}
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		oversized := strings.NewReader("{" + strings.Repeat(" ", 5<<20) + "}")
+
+		if _, err := manager.ReadLicense(oversized); err == nil {
+			t.Error("Expected an error for an oversized license")
+		}
+	})
+
+	t.Run("NoPublicKey", func(t *testing.T) {
+		signatureVerifier, err := licenser.NewSignatureVerifier(manager.PublicKey(), licenser.AlgorithmEdDSA)
+		if err != nil {
+			t.Fatalf("Failed to create signature verifier: %v", err)
+		}
+
+		// A custom signature verifier carries no exportable public key.
+		verifier, err := licenser.NewVerifier(licenser.Config{Verifier: signatureVerifier})
+		if err != nil {
+			t.Fatalf("Failed to create verifier: %v", err)
+		}
+
+		if err := verifier.WritePublicKey(&bytes.Buffer{}); !errors.Is(err, licenser.ErrNoPublicKey) {
+			t.Errorf("Expected ErrNoPublicKey, got %v", err)
+		}
+	})
+}
+
+func TestFileWrites(t *testing.T) {
+	manager := newEd25519Manager(t, licenser.Config{})
+
+	license := licenser.License{
+		Customer: "File Customer",
+		AppID:    "file-app",
+		Services: []licenser.Service{{ID: "test", Name: "Test"}},
+	}
+
+	signedLicense, err := manager.GenerateLicense(&license)
+	if err != nil {
+		t.Fatalf("Failed to generate license: %v", err)
+	}
+
+	dir := t.TempDir()
+	privateKeyPath := filepath.Join(dir, "private.pem")
+	publicKeyPath := filepath.Join(dir, "public.pem")
+	licensePath := filepath.Join(dir, "license.json")
+
+	if err := manager.SaveKeys(privateKeyPath, publicKeyPath); err != nil {
+		t.Fatalf("Failed to save keys: %v", err)
+	}
+
+	if err := manager.SaveLicense(signedLicense, licensePath); err != nil {
+		t.Fatalf("Failed to save license: %v", err)
+	}
+
+	t.Run("Modes", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("file modes are not supported on Windows")
+		}
+
+		for path, expected := range map[string]os.FileMode{
+			privateKeyPath: licenser.DefaultPrivateKeyFileMode,
+			publicKeyPath:  licenser.DefaultPublicKeyFileMode,
+			licensePath:    licenser.DefaultLicenseFileMode,
+		} {
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatalf("Failed to stat %s: %v", path, err)
+			}
+
+			if info.Mode().Perm() != expected {
+				t.Errorf("Expected %s to have mode %v, got %v", filepath.Base(path), expected, info.Mode().Perm())
+			}
+		}
+
+		custom := newEd25519Manager(t, licenser.Config{LicenseFileMode: 0640})
+		customPath := filepath.Join(dir, "custom.json")
+
+		if err := custom.SaveLicense(signedLicense, customPath); err != nil {
+			t.Fatalf("Failed to save license: %v", err)
+		}
+
+		info, err := os.Stat(customPath)
+		if err != nil {
+			t.Fatalf("Failed to stat license: %v", err)
+		}
+
+		if info.Mode().Perm() != 0640 {
+			t.Errorf("Expected configured mode 0640, got %v", info.Mode().Perm())
+		}
+	})
+
+	t.Run("RefuseOverwritePrivateKey", func(t *testing.T) {
+		original, err := os.ReadFile(privateKeyPath)
+		if err != nil {
+			t.Fatalf("Failed to read private key: %v", err)
+		}
+
+		other := newEd25519Manager(t, licenser.Config{})
+
+		err = other.SaveKeys(privateKeyPath, filepath.Join(dir, "other-public.pem"))
+		if !errors.Is(err, licenser.ErrPrivateKeyExists) {
+			t.Fatalf("Expected ErrPrivateKeyExists, got %v", err)
+		}
+
+		current, err := os.ReadFile(privateKeyPath)
+		if err != nil {
+			t.Fatalf("Failed to read private key: %v", err)
+		}
+
+		if !bytes.Equal(current, original) {
+			t.Error("Existing private key should not be replaced")
+		}
+	})
+
+	t.Run("OverwritePrivateKey", func(t *testing.T) {
+		other := newEd25519Manager(t, licenser.Config{OverwritePrivateKey: true})
+
+		if err := other.SaveKeys(privateKeyPath, publicKeyPath); err != nil {
+			t.Fatalf("Failed to overwrite keys: %v", err)
+		}
+
+		reloaded, err := licenser.NewManager(licenser.Config{GeneratorMode: true, PrivateKeyPath: privateKeyPath})
+		if err != nil {
+			t.Fatalf("Failed to load overwritten key: %v", err)
+		}
+
+		if reloaded.KeyID() != other.KeyID() {
+			t.Errorf("Expected key ID '%s', got '%s'", other.KeyID(), reloaded.KeyID())
+		}
+	})
+
+	t.Run("FailedWriteKeepsFile", func(t *testing.T) {
+		original, err := os.ReadFile(licensePath)
+		if err != nil {
+			t.Fatalf("Failed to read license: %v", err)
+		}
+
+		// A JSON license cannot be written as COSE, so the write fails midway.
+		coseManager := newEd25519Manager(t, licenser.Config{FileFormat: licenser.FileFormatCOSE})
+		if err := coseManager.SaveLicense(signedLicense, licensePath); err == nil {
+			t.Fatal("Expected an error saving a JSON license as COSE")
+		}
+
+		current, err := os.ReadFile(licensePath)
+		if err != nil {
+			t.Fatalf("Failed to read license: %v", err)
+		}
+
+		if !bytes.Equal(current, original) {
+			t.Error("Failed write should leave the existing license intact")
+		}
+	})
+
+	t.Run("NoTemporaryFiles", func(t *testing.T) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatalf("Failed to read directory: %v", err)
+		}
+
+		for _, entry := range entries {
+			if strings.HasSuffix(entry.Name(), ".tmp") {
+				t.Errorf("Unexpected temporary file %s", entry.Name())
+			}
+		}
+	})
+}
@@ -0,0 +1,289 @@
+/*******************************************************************
+
+		::          ::        +--------+-----------------------+
+		  ::      ::          | Author | Dmitry Novikov        |
+		::::::::::::::        | Email  | dredfort.42@gmail.com |
+	  ::::  ::::::  ::::      +--------+-----------------------+
+	::::::::::::::::::::::
+	::  ::::::::::::::  ::    File     | issuer.go
+	::  ::          ::  ::    Created  | 2026-10-16
+		  ::::  ::::          Modified | 2026-10-16
+
+	GitHub:   https://github.com/dredfort42
+	LinkedIn: https://linkedin.com/in/novikov-da
+
+*******************************************************************/
+
+package licenser
+
+import (
+	"crypto"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+)
+
+// Issuer signs licenses. It owns the private key and is only needed by the
+// tools that issue licenses; products that only check licenses use a Verifier.
+type Issuer struct {
+	privateKey crypto.Signer
+	signer     Signer
+	keyID      string
+	config     Config
+}
+
+// NewIssuer creates a license issuer from the private key settings of config:
+// Signer, PrivateKeyPEM or PrivateKeyPath. Without any of them a new key is
+// generated. Config.GeneratorMode is not required.
+func NewIssuer(config Config) (*Issuer, error) {
+	i := &Issuer{config: config.withDefaults()}
+
+	if err := i.setupSigner(); err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+// setupSigner installs the configured signer or builds one from the private key.
+func (i *Issuer) setupSigner() error {
+	if i.config.Signer != nil {
+		i.signer = i.config.Signer
+
+		if i.config.Algorithm == "" {
+			i.config.Algorithm = i.signer.Algorithm()
+		}
+
+		return i.setupSigningKeyID()
+	}
+
+	var err error
+
+	// Load or generate private key
+	switch {
+	case i.config.PrivateKeyPEM != "":
+		i.privateKey, err = parsePrivateKeyFromPEM(i.config.PrivateKeyPEM, i.passphrase)
+	case i.config.PrivateKeyPath != "":
+		i.privateKey, err = loadPrivateKeyFromFile(i.config.FS, i.config.PrivateKeyPath, i.passphrase)
+	default:
+		i.privateKey, err = generateKey(i.config.Algorithm, i.config.KeySize)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to setup private key: %w", err)
+	}
+
+	if i.config.Algorithm == "" {
+		i.config.Algorithm = algorithmForKey(i.privateKey.Public())
+	}
+
+	i.signer, err = NewSigner(i.privateKey, i.config.Algorithm)
+	if err != nil {
+		return fmt.Errorf("failed to setup signer: %w", err)
+	}
+
+	return i.setupSigningKeyID()
+}
+
+// setupSigningKeyID resolves the key ID stamped on issued licenses.
+func (i *Issuer) setupSigningKeyID() error {
+	if i.config.KeyID != "" {
+		i.keyID = i.config.KeyID
+
+		return nil
+	}
+
+	keyID, err := KeyFingerprint(i.signer.Public())
+	if err != nil {
+		return fmt.Errorf("failed to fingerprint signing key: %w", err)
+	}
+
+	i.keyID = keyID
+
+	return nil
+}
+
+// KeyID returns the ID stamped on issued licenses.
+func (i *Issuer) KeyID() string {
+	return i.keyID
+}
+
+// Algorithm returns the signing algorithm of issued licenses.
+func (i *Issuer) Algorithm() string {
+	return i.signer.Algorithm()
+}
+
+// PublicKey returns the public half of the signing key.
+func (i *Issuer) PublicKey() crypto.PublicKey {
+	return i.signer.Public()
+}
+
+// GenerateLicense creates a signed license. With Config.FileFormat set to
+// FileFormatCOSE the license is signed as a COSE_Sign1 message.
+func (i *Issuer) GenerateLicense(license *License) (*SignedLicense, error) {
+	if err := i.prepareLicense(license); err != nil {
+		return nil, err
+	}
+
+	if i.config.FileFormat == FileFormatCOSE {
+		return i.generateCOSE(license)
+	}
+
+	data, err := encodePayload(license)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal license: %w", err)
+	}
+
+	signature, err := signLegacy(i.signer, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign license: %w", err)
+	}
+
+	return &SignedLicense{
+		Data:      *license,
+		Payload:   base64.StdEncoding.EncodeToString(data),
+		Signature: base64.StdEncoding.EncodeToString(signature),
+		KeyID:     i.keyID,
+		CreatedAt: i.config.Clock.Now().Unix(),
+		Algorithm: i.signer.Algorithm(),
+	}, nil
+}
+
+// prepareLicense checks that a license can be issued and fills in defaults.
+func (i *Issuer) prepareLicense(license *License) error {
+	now := i.config.Clock.Now().Unix()
+
+	if i.config.KeyRetiresAt > 0 && now > i.config.KeyRetiresAt {
+		return fmt.Errorf("%w: %s", ErrKeyRetired, i.keyID)
+	}
+
+	if license.Customer == "" {
+		return ErrCustomerRequired
+	}
+
+	if license.AppID == "" {
+		return ErrAppIDRequired
+	}
+
+	if len(license.Services) == 0 {
+		return ErrNoServicesAllowed
+	}
+
+	if license.NotBefore > 0 && license.ExpiresAt > 0 && license.NotBefore >= license.ExpiresAt {
+		return ErrInvalidValidityPeriod
+	}
+
+	if license.IssuedAt == 0 {
+		license.IssuedAt = now
+	}
+
+	return nil
+}
+
+func (i *Issuer) signData(data []byte) (string, error) {
+	signature, err := i.signer.Sign(data)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// SaveLicense saves a license to file in the encoding selected by
+// Config.FileFormat. The file is replaced atomically, so a crash never leaves
+// a truncated license behind.
+func (i *Issuer) SaveLicense(signedLicense *SignedLicense, filePath string) error {
+	return writeFile(filePath, i.config.LicenseFileMode, true, func(w io.Writer) error {
+		return i.WriteLicense(w, signedLicense)
+	})
+}
+
+// SaveKeys saves private and public keys to files. The private key is
+// encrypted when a passphrase is configured, and an existing private key file
+// is only replaced when Config.OverwritePrivateKey is set.
+func (i *Issuer) SaveKeys(privateKeyPath, publicKeyPath string) error {
+	err := writeFile(privateKeyPath, i.config.PrivateKeyFileMode, i.config.OverwritePrivateKey, i.WritePrivateKey)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("%w: %s", ErrPrivateKeyExists, privateKeyPath)
+	} else if err != nil {
+		return fmt.Errorf("failed to save private key: %w", err)
+	}
+
+	if err := writeFile(publicKeyPath, i.config.PublicKeyFileMode, true, i.WritePublicKey); err != nil {
+		return fmt.Errorf("failed to save public key: %w", err)
+	}
+
+	return nil
+}
+
+// ExportKeys returns both private and public keys as PEM strings.
+func (i *Issuer) ExportKeys() (privateKey string, publicKey string, err error) {
+	if privateKey, err = i.ExportPrivateKey(); err != nil {
+		return "", "", err
+	}
+
+	if publicKey, err = i.ExportPublicKey(); err != nil {
+		return "", "", err
+	}
+
+	return privateKey, publicKey, nil
+}
+
+// ExportPrivateKey exports the private key as PEM.
+// RSA keys are encoded as PKCS#1, ECDSA keys as SEC1 and Ed25519 keys as
+// PKCS#8; with Config.PKCS8 set every key type is encoded as PKCS#8.
+// When a passphrase is configured the key is exported as encrypted PKCS#8.
+// Issuers backed by a custom Signer return ErrNoPrivateKey.
+func (i *Issuer) ExportPrivateKey() (string, error) {
+	if i.privateKey == nil {
+		return "", ErrNoPrivateKey
+	}
+
+	passphrase, err := i.passphrase()
+	if err != nil {
+		return "", fmt.Errorf("failed to get passphrase: %w", err)
+	}
+
+	if passphrase != "" {
+		return i.ExportEncryptedPrivateKey(passphrase)
+	}
+
+	block, err := marshalPrivateKey(i.privateKey, i.config.PKCS8)
+	if err != nil {
+		return "", err
+	}
+
+	return string(pem.EncodeToMemory(block)), nil
+}
+
+// ExportEncryptedPrivateKey exports the private key as PKCS#8 encrypted with
+// passphrase (PBES2, PBKDF2-HMAC-SHA256 and AES-256-CBC).
+func (i *Issuer) ExportEncryptedPrivateKey(passphrase string) (string, error) {
+	if i.privateKey == nil {
+		return "", ErrNoPrivateKey
+	}
+
+	block, err := marshalEncryptedPrivateKey(i.privateKey, passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	return string(pem.EncodeToMemory(block)), nil
+}
+
+// passphrase returns the configured private key passphrase, if any.
+func (i *Issuer) passphrase() (string, error) {
+	if i.config.Passphrase != "" || i.config.PassphraseFunc == nil {
+		return i.config.Passphrase, nil
+	}
+
+	return i.config.PassphraseFunc()
+}
+
+// ExportPublicKey exports the public half of the signing key as PEM.
+func (i *Issuer) ExportPublicKey() (string, error) {
+	return encodePublicKeyPEM(i.signer.Public())
+}
@@ -0,0 +1,119 @@
+/*******************************************************************
+
+		::          ::        +--------+-----------------------+
+		  ::      ::          | Author | Dmitry Novikov        |
+		::::::::::::::        | Email  | dredfort.42@gmail.com |
+	  ::::  ::::::  ::::      +--------+-----------------------+
+	::::::::::::::::::::::
+	::  ::::::::::::::  ::    File     | issuer_test.go
+	::  ::          ::  ::    Created  | 2026-10-16
+		  ::::  ::::          Modified | 2026-10-16
+
+	GitHub:   https://github.com/dredfort42
+	LinkedIn: https://linkedin.com/in/novikov-da
+
+*******************************************************************/
+
+package licenser_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	licenser "github.com/dredfort42/go_licenser"
+)
+
+func TestIssuerAndVerifier(t *testing.T) {
+	// Issuers do not need GeneratorMode.
+	issuer, err := licenser.NewIssuer(licenser.Config{Algorithm: licenser.AlgorithmEdDSA})
+	if err != nil {
+		t.Fatalf("Failed to create issuer: %v", err)
+	}
+
+	verifier, err := licenser.NewVerifier(licenser.Config{PublicKeyPEM: exportPublicKey(t, issuer)})
+	if err != nil {
+		t.Fatalf("Failed to create verifier: %v", err)
+	}
+
+	license := licenser.License{
+		Customer: "Split Customer",
+		AppID:    "split-app",
+		Services: []licenser.Service{{ID: "test", Name: "Test"}},
+	}
+
+	t.Run("IssueAndVerify", func(t *testing.T) {
+		signedLicense, err := issuer.GenerateLicense(&license)
+		if err != nil {
+			t.Fatalf("Failed to generate license: %v", err)
+		}
+
+		if signedLicense.KeyID != verifier.KeyID() {
+			t.Errorf("Expected key ID '%s', got '%s'", verifier.KeyID(), signedLicense.KeyID)
+		}
+
+		if result := verifier.ValidateLicense(signedLicense); !result.Valid {
+			t.Errorf("Issued license should be valid, errors: %v", result.Errors)
+		}
+	})
+
+	t.Run("Token", func(t *testing.T) {
+		token, err := issuer.GenerateToken(&license)
+		if err != nil {
+			t.Fatalf("Failed to generate token: %v", err)
+		}
+
+		if _, result, err := verifier.ValidateToken(token); err != nil || !result.Valid {
+			t.Errorf("Issued token should be valid, got %v %v", result, err)
+		}
+	})
+
+	t.Run("VerifierRequiresPublicKey", func(t *testing.T) {
+		// Private key settings are ignored by verifiers.
+		_, err := licenser.NewVerifier(licenser.Config{PrivateKeyPEM: exportPrivateKey(t, issuer), GeneratorMode: true})
+		if !errors.Is(err, licenser.ErrNoPublicKey) {
+			t.Errorf("Expected ErrNoPublicKey, got %v", err)
+		}
+	})
+
+	t.Run("VerifierHasNoIssuance", func(t *testing.T) {
+		verifierType := reflect.TypeOf(verifier)
+
+		for i := range verifierType.NumMethod() {
+			name := verifierType.Method(i).Name
+			if strings.Contains(name, "Private") || strings.HasPrefix(name, "Generate") || name == "SaveKeys" {
+				t.Errorf("Verifier should not expose %s", name)
+			}
+		}
+	})
+
+	t.Run("ManagerHalves", func(t *testing.T) {
+		generator := newEd25519Manager(t, licenser.Config{})
+		if generator.Issuer() == nil || generator.Verifier() == nil {
+			t.Fatal("Generator manager should have an issuer and a verifier")
+		}
+
+		validator, err := licenser.NewManager(licenser.Config{PublicKeyPEM: exportPublicKey(t, generator)})
+		if err != nil {
+			t.Fatalf("Failed to create validator: %v", err)
+		}
+
+		if validator.Issuer() != nil {
+			t.Error("Validator manager should not have an issuer")
+		}
+
+		signedLicense, err := generator.Issuer().GenerateLicense(&license)
+		if err != nil {
+			t.Fatalf("Failed to generate license: %v", err)
+		}
+
+		if result := validator.Verifier().ValidateLicense(signedLicense); !result.Valid {
+			t.Errorf("License should be valid, errors: %v", result.Errors)
+		}
+
+		if _, err := validator.GenerateToken(&license); !errors.Is(err, licenser.ErrGeneratorModeRequired) {
+			t.Errorf("Expected ErrGeneratorModeRequired, got %v", err)
+		}
+	})
+}
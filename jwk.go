@@ -0,0 +1,318 @@
+/*******************************************************************
+
+		::          ::        +--------+-----------------------+
+		  ::      ::          | Author | Dmitry Novikov        |
+		::::::::::::::        | Email  | dredfort.42@gmail.com |
+	  ::::  ::::::  ::::      +--------+-----------------------+
+	::::::::::::::::::::::
+	::  ::::::::::::::  ::    File     | jwk.go
+	::  ::          ::  ::    Created  | 2026-10-16
+		  ::::  ::::          Modified | 2026-10-16
+
+	GitHub:   https://github.com/dredfort42
+	LinkedIn: https://linkedin.com/in/novikov-da
+
+*******************************************************************/
+
+package licenser
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"slices"
+)
+
+// jwkUseSignature marks a JWK as a signature verification key.
+const jwkUseSignature = "sig"
+
+// JWK is a public JSON Web Key (RFC 7517). RSA keys use N and E, ECDSA keys
+// Curve, X and Y, and Ed25519 keys (RFC 8037) Curve and X.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use,omitempty"`
+	KeyID     string `json:"kid,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set (RFC 7517 section 5).
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// jwkCurves maps the supported EC curve names to their implementations.
+var jwkCurves = map[string]ecdh.Curve{
+	"P-256": ecdh.P256(),
+	"P-384": ecdh.P384(),
+}
+
+// NewJWK converts a public key to a JWK with the given key ID and algorithm.
+func NewJWK(publicKey crypto.PublicKey, keyID, algorithm string) (*JWK, error) {
+	jwk := &JWK{Use: jwkUseSignature, KeyID: keyID, Algorithm: algorithm}
+
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = jwsEncoding.EncodeToString(key.N.Bytes())
+		jwk.E = jwsEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	case *ecdsa.PublicKey:
+		ecdhKey, err := key.ECDH()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidPublicKey, err)
+		}
+
+		// Uncompressed point: 0x04 || X || Y, each padded to the curve size.
+		point := ecdhKey.Bytes()
+		size := (len(point) - 1) / 2
+
+		jwk.KeyType = "EC"
+		jwk.Curve = key.Curve.Params().Name
+		jwk.X = jwsEncoding.EncodeToString(point[1 : 1+size])
+		jwk.Y = jwsEncoding.EncodeToString(point[1+size:])
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = jwsEncoding.EncodeToString(key)
+	case nil:
+		return nil, ErrNoPublicKey
+	default:
+		return nil, fmt.Errorf("%w: unsupported key type %T", ErrInvalidPublicKey, publicKey)
+	}
+
+	return jwk, nil
+}
+
+// ExportPublicKeyJWK exports the public key as a JSON Web Key whose kid is
+// the ID stamped on licenses.
+func (m *Manager) ExportPublicKeyJWK() (string, error) {
+	return m.verifier.ExportPublicKeyJWK()
+}
+
+// ExportPublicKeyJWK exports the public half of the signing key as a JSON Web
+// Key whose kid is the ID stamped on issued licenses.
+func (i *Issuer) ExportPublicKeyJWK() (string, error) {
+	return marshalJWK(i.signer.Public(), i.keyID, i.signer.Algorithm())
+}
+
+// ExportPublicKeyJWK exports the public key as a JSON Web Key whose kid is
+// the ID licenses signed by it carry. Since an imported alg restricts its
+// key, alg is only set when Config.Algorithm names it or, for a manager in
+// generator mode, the issuer signs with it.
+func (v *Verifier) ExportPublicKeyJWK() (string, error) {
+	return marshalJWK(v.publicKey, v.KeyID(), v.algorithm)
+}
+
+func marshalJWK(publicKey crypto.PublicKey, keyID, algorithm string) (string, error) {
+	jwk, err := NewJWK(publicKey, keyID, algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(jwk)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JWK: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// PublicKey converts the JWK back to a public key. EC points are checked to
+// lie on the curve.
+func (k *JWK) PublicKey() (crypto.PublicKey, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := decodeJWKMember(k.N, "n")
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeJWKMember(k.E, "e")
+		if err != nil {
+			return nil, err
+		}
+
+		if len(e) > 4 {
+			return nil, fmt.Errorf("%w: exponent too large", ErrInvalidJWK)
+		}
+
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		return k.ecdsaPublicKey()
+	case "OKP":
+		if k.Curve != "Ed25519" {
+			return nil, fmt.Errorf("%w: unsupported curve %q", ErrInvalidJWK, k.Curve)
+		}
+
+		x, err := decodeJWKMember(k.X, "x")
+		if err != nil {
+			return nil, err
+		}
+
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("%w: Ed25519 key must be %d bytes", ErrInvalidJWK, ed25519.PublicKeySize)
+		}
+
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported key type %q", ErrInvalidJWK, k.KeyType)
+	}
+}
+
+func (k *JWK) ecdsaPublicKey() (crypto.PublicKey, error) {
+	curve, ok := jwkCurves[k.Curve]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported curve %q", ErrInvalidJWK, k.Curve)
+	}
+
+	x, err := decodeJWKMember(k.X, "x")
+	if err != nil {
+		return nil, err
+	}
+
+	y, err := decodeJWKMember(k.Y, "y")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(x) != len(y) {
+		return nil, fmt.Errorf("%w: coordinates differ in length", ErrInvalidJWK)
+	}
+
+	// NewPublicKey rejects points of the wrong size or off the curve.
+	ecdhKey, err := curve.NewPublicKey(append(append([]byte{4}, x...), y...))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidJWK, err)
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(ecdhKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidJWK, err)
+	}
+
+	return x509.ParsePKIXPublicKey(der)
+}
+
+// supported reports whether the key is a signature key this package can use.
+// Other keys in a set, such as encryption keys, are skipped.
+func (k *JWK) supported() bool {
+	if k.Use != "" && k.Use != jwkUseSignature {
+		return false
+	}
+
+	if k.Algorithm != "" && !slices.Contains(supportedAlgorithms, k.Algorithm) {
+		return false
+	}
+
+	switch k.KeyType {
+	case "RSA":
+		return true
+	case "EC":
+		_, ok := jwkCurves[k.Curve]
+
+		return ok
+	case "OKP":
+		return k.Curve == "Ed25519"
+	default:
+		return false
+	}
+}
+
+func decodeJWKMember(value, name string) ([]byte, error) {
+	if value == "" {
+		return nil, fmt.Errorf("%w: missing %q", ErrInvalidJWK, name)
+	}
+
+	b, err := jwsEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q is not base64url", ErrInvalidJWK, name)
+	}
+
+	return b, nil
+}
+
+// ParseJWKS decodes a JSON Web Key Set.
+func ParseJWKS(data []byte) (*JWKS, error) {
+	var jwks JWKS
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidJWK, err)
+	}
+
+	if jwks.Keys == nil {
+		return nil, fmt.Errorf("%w: missing \"keys\"", ErrInvalidJWK)
+	}
+
+	return &jwks, nil
+}
+
+// TrustedKeys converts the signature keys of the set to trusted keys. A key's
+// kid becomes its key ID and its alg, if present, the only algorithm accepted
+// for it. Keys for other uses or of unsupported types are skipped.
+func (s *JWKS) TrustedKeys() ([]TrustedKey, error) {
+	keys := make([]TrustedKey, 0, len(s.Keys))
+
+	for i := range s.Keys {
+		jwk := &s.Keys[i]
+		if !jwk.supported() {
+			continue
+		}
+
+		publicKey, err := jwk.PublicKey()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load JWKS key %d: %w", i, err)
+		}
+
+		keys = append(keys, TrustedKey{KeyID: jwk.KeyID, PublicKey: publicKey, Algorithm: jwk.Algorithm})
+	}
+
+	return keys, nil
+}
+
+// ExportJWKS exports every public key accepted during validation as a JSON
+// Web Key Set, with each kid matching SignedLicense.KeyID.
+func (m *Manager) ExportJWKS() (string, error) {
+	return m.verifier.ExportJWKS()
+}
+
+// ExportJWKS exports every public key accepted during validation as a JSON
+// Web Key Set, with each kid matching SignedLicense.KeyID. Keys behind a
+// custom SignatureVerifier have no public key and are left out. Since an
+// imported alg restricts its key, alg is only set for restricted keys.
+func (v *Verifier) ExportJWKS() (string, error) {
+	jwks := JWKS{Keys: make([]JWK, 0, len(v.keys))}
+
+	for _, id := range v.TrustedKeyIDs() {
+		key := v.keys[id]
+		if key.publicKey == nil {
+			continue
+		}
+
+		jwk, err := NewJWK(key.publicKey, key.id, key.algorithm)
+		if err != nil {
+			return "", err
+		}
+
+		jwks.Keys = append(jwks.Keys, *jwk)
+	}
+
+	if len(jwks.Keys) == 0 {
+		return "", ErrNoPublicKey
+	}
+
+	data, err := json.MarshalIndent(jwks, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JWKS: %w", err)
+	}
+
+	return string(data), nil
+}
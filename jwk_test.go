@@ -0,0 +1,454 @@
+/*******************************************************************
+
+		::          ::        +--------+-----------------------+
+		  ::      ::          | Author | Dmitry Novikov        |
+		::::::::::::::        | Email  | dredfort.42@gmail.com |
+	  ::::  ::::::  ::::      +--------+-----------------------+
+	::::::::::::::::::::::
+	::  ::::::::::::::  ::    File     | jwk_test.go
+	::  ::          ::  ::    Created  | 2026-10-16
+		  ::::  ::::          Modified | 2026-10-16
+
+	GitHub:   https://github.com/dredfort42
+	LinkedIn: https://linkedin.com/in/novikov-da
+
+*******************************************************************/
+
+package licenser_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	licenser "github.com/dredfort42/go_licenser"
+)
+
+func decodeJWK(t *testing.T, data string) licenser.JWK {
+	t.Helper()
+
+	var jwk licenser.JWK
+	if err := json.Unmarshal([]byte(data), &jwk); err != nil {
+		t.Fatalf("Failed to unmarshal JWK: %v", err)
+	}
+
+	return jwk
+}
+
+func decodeJWKInt(t *testing.T, value string) *big.Int {
+	t.Helper()
+
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		t.Fatalf("Failed to decode JWK member: %v", err)
+	}
+
+	return new(big.Int).SetBytes(b)
+}
+
+func TestPublicKeyJWK(t *testing.T) {
+	t.Run("RSA", func(t *testing.T) {
+		manager, err := licenser.NewManager(licenser.Config{GeneratorMode: true, KeySize: 1024})
+		if err != nil {
+			t.Fatalf("Failed to create manager: %v", err)
+		}
+
+		data, err := manager.ExportPublicKeyJWK()
+		if err != nil {
+			t.Fatalf("Failed to export JWK: %v", err)
+		}
+
+		jwk := decodeJWK(t, data)
+		publicKey := manager.GetPublicKey()
+
+		if jwk.KeyType != "RSA" || jwk.Algorithm != licenser.AlgorithmRS256 || jwk.Use != "sig" {
+			t.Errorf("Unexpected JWK header members: %s", data)
+		}
+
+		if jwk.KeyID != manager.KeyID() {
+			t.Errorf("Expected kid '%s', got '%s'", manager.KeyID(), jwk.KeyID)
+		}
+
+		if decodeJWKInt(t, jwk.N).Cmp(publicKey.N) != 0 || decodeJWKInt(t, jwk.E).Int64() != int64(publicKey.E) {
+			t.Error("JWK modulus and exponent should match the public key")
+		}
+
+		if jwk.E != "AQAB" {
+			t.Errorf("Expected exponent AQAB, got %s", jwk.E)
+		}
+	})
+
+	t.Run("EC", func(t *testing.T) {
+		manager, err := licenser.NewManager(licenser.Config{GeneratorMode: true, Algorithm: licenser.AlgorithmES384})
+		if err != nil {
+			t.Fatalf("Failed to create manager: %v", err)
+		}
+
+		data, err := manager.ExportPublicKeyJWK()
+		if err != nil {
+			t.Fatalf("Failed to export JWK: %v", err)
+		}
+
+		jwk := decodeJWK(t, data)
+		publicKey := manager.PublicKey().(*ecdsa.PublicKey)
+
+		if jwk.KeyType != "EC" || jwk.Curve != "P-384" || jwk.Algorithm != licenser.AlgorithmES384 {
+			t.Errorf("Unexpected JWK header members: %s", data)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil || len(x) != 48 {
+			t.Fatalf("Expected a 48-byte x coordinate, got %d bytes (%v)", len(x), err)
+		}
+
+		der, err := x509.MarshalPKIXPublicKey(publicKey)
+		if err != nil {
+			t.Fatalf("Failed to marshal public key: %v", err)
+		}
+
+		// The PKIX encoding ends with the uncompressed point 0x04 || X || Y.
+		point := append(append([]byte{4}, x...), decodeJWKInt(t, jwk.Y).FillBytes(make([]byte, 48))...)
+		if !bytes.HasSuffix(der, point) {
+			t.Error("JWK coordinates should match the public key")
+		}
+	})
+
+	t.Run("Ed25519", func(t *testing.T) {
+		manager := newEd25519Manager(t, licenser.Config{KeyID: "signing-2026"})
+
+		data, err := manager.ExportPublicKeyJWK()
+		if err != nil {
+			t.Fatalf("Failed to export JWK: %v", err)
+		}
+
+		jwk := decodeJWK(t, data)
+
+		if jwk.KeyType != "OKP" || jwk.Curve != "Ed25519" || jwk.KeyID != "signing-2026" {
+			t.Errorf("Unexpected JWK header members: %s", data)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil || !bytes.Equal(x, manager.PublicKey().(ed25519.PublicKey)) {
+			t.Error("JWK x should be the raw Ed25519 public key")
+		}
+	})
+
+	t.Run("PS256RoundTrip", func(t *testing.T) {
+		issuer, err := licenser.NewManager(licenser.Config{
+			GeneratorMode: true,
+			Algorithm:     licenser.AlgorithmPS256,
+			KeySize:       1024,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create manager: %v", err)
+		}
+
+		verifier, err := licenser.NewVerifier(licenser.Config{PublicKeyPEM: exportPublicKey(t, issuer)})
+		if err != nil {
+			t.Fatalf("Failed to create verifier: %v", err)
+		}
+
+		// The verifier cannot tell RS256 from PS256 keys, so it sets no alg.
+		data := must(t, verifier.ExportPublicKeyJWK)
+		if jwk := decodeJWK(t, data); jwk.Algorithm != "" {
+			t.Errorf("Expected no alg for a key of unknown algorithm, got %s", jwk.Algorithm)
+		}
+
+		jwksPath := filepath.Join(t.TempDir(), "jwks.json")
+		if err := os.WriteFile(jwksPath, []byte(`{"keys":[`+data+`]}`), 0600); err != nil {
+			t.Fatalf("Failed to write JWKS: %v", err)
+		}
+
+		imported, err := licenser.NewVerifier(licenser.Config{TrustedKeysJWKSPath: jwksPath})
+		if err != nil {
+			t.Fatalf("Failed to create verifier: %v", err)
+		}
+
+		signedLicense, err := issuer.GenerateLicense(&licenser.License{
+			Customer: "JWK Customer",
+			AppID:    "jwk-app",
+			Services: []licenser.Service{{ID: "test", Name: "Test"}},
+		})
+		if err != nil {
+			t.Fatalf("Failed to generate license: %v", err)
+		}
+
+		if result := imported.ValidateLicense(signedLicense); !result.Valid {
+			t.Errorf("PS256 license should be valid with the exported JWK, errors: %v", result.Errors)
+		}
+	})
+
+	t.Run("NoPublicKey", func(t *testing.T) {
+		if _, err := licenser.NewJWK(nil, "", ""); !errors.Is(err, licenser.ErrNoPublicKey) {
+			t.Errorf("Expected ErrNoPublicKey, got %v", err)
+		}
+
+		if _, err := licenser.NewJWK(&rsa.PrivateKey{}, "", ""); !errors.Is(err, licenser.ErrInvalidPublicKey) {
+			t.Errorf("Expected ErrInvalidPublicKey, got %v", err)
+		}
+	})
+}
+
+func TestKeyFingerprintSHA256(t *testing.T) {
+	manager := newEd25519Manager(t, licenser.Config{})
+
+	fingerprint, err := manager.Fingerprint()
+	if err != nil {
+		t.Fatalf("Failed to fingerprint key: %v", err)
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(manager.PublicKey())
+	if err != nil {
+		t.Fatalf("Failed to marshal public key: %v", err)
+	}
+
+	digest := sha256.Sum256(der)
+	hexParts := make([]string, len(digest))
+
+	for i, b := range digest {
+		hexParts[i] = fmt.Sprintf("%02x", b)
+	}
+
+	if expected := strings.Join(hexParts, ":"); fingerprint != expected {
+		t.Errorf("Expected fingerprint %s, got %s", expected, fingerprint)
+	}
+
+	// The short key ID is a prefix of the full fingerprint.
+	if !strings.HasPrefix(strings.ReplaceAll(fingerprint, ":", ""), manager.KeyID()) {
+		t.Errorf("Expected key ID %s to prefix fingerprint %s", manager.KeyID(), fingerprint)
+	}
+
+	if issuerFingerprint, err := manager.Issuer().Fingerprint(); err != nil || issuerFingerprint != fingerprint {
+		t.Errorf("Expected issuer fingerprint %s, got %s (%v)", fingerprint, issuerFingerprint, err)
+	}
+
+	if _, err := licenser.KeyFingerprintSHA256(nil); !errors.Is(err, licenser.ErrNoPublicKey) {
+		t.Errorf("Expected ErrNoPublicKey, got %v", err)
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	license := licenser.License{
+		Customer: "JWKS Customer",
+		AppID:    "jwks-app",
+		Services: []licenser.Service{{ID: "test", Name: "Test"}},
+	}
+
+	oldIssuer := newEd25519Manager(t, licenser.Config{})
+
+	newIssuer, err := licenser.NewManager(licenser.Config{GeneratorMode: true, Algorithm: licenser.AlgorithmES256})
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	publisher, err := licenser.NewManager(licenser.Config{
+		PublicKeyPEM: exportPublicKey(t, newIssuer),
+		TrustedKeys:  []licenser.TrustedKey{{PublicKeyPEM: exportPublicKey(t, oldIssuer)}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create publisher: %v", err)
+	}
+
+	jwksData, err := publisher.ExportJWKS()
+	if err != nil {
+		t.Fatalf("Failed to export JWKS: %v", err)
+	}
+
+	dir := t.TempDir()
+	jwksPath := filepath.Join(dir, "jwks.json")
+
+	if err := os.WriteFile(jwksPath, []byte(jwksData), 0600); err != nil {
+		t.Fatalf("Failed to write JWKS: %v", err)
+	}
+
+	t.Run("Export", func(t *testing.T) {
+		jwks, err := licenser.ParseJWKS([]byte(jwksData))
+		if err != nil {
+			t.Fatalf("Failed to parse JWKS: %v", err)
+		}
+
+		if len(jwks.Keys) != 2 {
+			t.Fatalf("Expected 2 keys, got %d", len(jwks.Keys))
+		}
+
+		for _, jwk := range jwks.Keys {
+			if jwk.KeyID != oldIssuer.KeyID() && jwk.KeyID != newIssuer.KeyID() {
+				t.Errorf("Unexpected kid '%s'", jwk.KeyID)
+			}
+		}
+	})
+
+	t.Run("LoadFromFile", func(t *testing.T) {
+		verifier, err := licenser.NewVerifier(licenser.Config{TrustedKeysJWKSPath: jwksPath})
+		if err != nil {
+			t.Fatalf("Failed to create verifier: %v", err)
+		}
+
+		for name, issuer := range map[string]*licenser.Manager{"Old": oldIssuer, "New": newIssuer} {
+			signedLicense, err := issuer.GenerateLicense(&license)
+			if err != nil {
+				t.Fatalf("Failed to generate license: %v", err)
+			}
+
+			if result := verifier.ValidateLicense(signedLicense); !result.Valid {
+				t.Errorf("%s license should be valid with JWKS keys, errors: %v", name, result.Errors)
+			}
+		}
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		rsaManager, err := licenser.NewManager(licenser.Config{GeneratorMode: true, KeySize: 1024})
+		if err != nil {
+			t.Fatalf("Failed to create manager: %v", err)
+		}
+
+		for name, manager := range map[string]*licenser.Manager{"RSA": rsaManager, "EC": newIssuer, "Ed25519": oldIssuer} {
+			data, err := manager.ExportPublicKeyJWK()
+			if err != nil {
+				t.Fatalf("Failed to export JWK: %v", err)
+			}
+
+			jwk := decodeJWK(t, data)
+
+			publicKey, err := jwk.PublicKey()
+			if err != nil {
+				t.Fatalf("Failed to convert %s JWK: %v", name, err)
+			}
+
+			if !publicKey.(interface{ Equal(crypto.PublicKey) bool }).Equal(manager.PublicKey()) {
+				t.Errorf("%s JWK should convert back to the same key", name)
+			}
+		}
+	})
+
+	t.Run("AlgorithmRestriction", func(t *testing.T) {
+		rsaManager, err := licenser.NewManager(licenser.Config{GeneratorMode: true, KeySize: 1024})
+		if err != nil {
+			t.Fatalf("Failed to create manager: %v", err)
+		}
+
+		jwk := decodeJWK(t, must(t, rsaManager.ExportPublicKeyJWK))
+		jwk.Algorithm = licenser.AlgorithmPS256
+
+		keys, err := (&licenser.JWKS{Keys: []licenser.JWK{jwk}}).TrustedKeys()
+		if err != nil {
+			t.Fatalf("Failed to convert JWKS: %v", err)
+		}
+
+		verifier, err := licenser.NewVerifier(licenser.Config{TrustedKeys: keys})
+		if err != nil {
+			t.Fatalf("Failed to create verifier: %v", err)
+		}
+
+		signedLicense, err := rsaManager.GenerateLicense(&license)
+		if err != nil {
+			t.Fatalf("Failed to generate license: %v", err)
+		}
+
+		result := verifier.ValidateLicense(signedLicense)
+		if result.Valid || !contains(strings.Join(result.Errors, ";"), licenser.ErrAlgorithmMismatch.Error()) {
+			t.Errorf("RS256 license should be rejected by a PS256-only key, errors: %v", result.Errors)
+		}
+	})
+
+	t.Run("UnrestrictedPrimaryKey", func(t *testing.T) {
+		rs256, err := licenser.NewManager(licenser.Config{GeneratorMode: true, KeySize: 1024})
+		if err != nil {
+			t.Fatalf("Failed to create manager: %v", err)
+		}
+
+		// The rotated manager signs PS256 with the same key and still accepts RS256.
+		ps256, err := licenser.NewManager(licenser.Config{
+			GeneratorMode: true,
+			PrivateKeyPEM: exportPrivateKey(t, rs256),
+			Algorithm:     licenser.AlgorithmPS256,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create manager: %v", err)
+		}
+
+		jwks, err := licenser.ParseJWKS([]byte(must(t, ps256.ExportJWKS)))
+		if err != nil {
+			t.Fatalf("Failed to parse JWKS: %v", err)
+		}
+
+		if len(jwks.Keys) != 1 || jwks.Keys[0].Algorithm != "" {
+			t.Fatalf("Expected one key without alg, got %+v", jwks.Keys)
+		}
+
+		keys, err := jwks.TrustedKeys()
+		if err != nil {
+			t.Fatalf("Failed to convert JWKS: %v", err)
+		}
+
+		verifier, err := licenser.NewVerifier(licenser.Config{TrustedKeys: keys})
+		if err != nil {
+			t.Fatalf("Failed to create verifier: %v", err)
+		}
+
+		for name, manager := range map[string]*licenser.Manager{"RS256": rs256, "PS256": ps256} {
+			signedLicense, err := manager.GenerateLicense(&license)
+			if err != nil {
+				t.Fatalf("Failed to generate license: %v", err)
+			}
+
+			if result := verifier.ValidateLicense(signedLicense); !result.Valid {
+				t.Errorf("%s license should be valid with the exported key, errors: %v", name, result.Errors)
+			}
+		}
+	})
+
+	t.Run("SkipsOtherKeys", func(t *testing.T) {
+		jwks, err := licenser.ParseJWKS([]byte(`{"keys":[
+			{"kty":"oct","k":"c2VjcmV0"},
+			{"kty":"RSA","use":"enc","n":"AQAB","e":"AQAB"},
+			{"kty":"EC","crv":"P-521","x":"AA","y":"AA"}
+		]}`))
+		if err != nil {
+			t.Fatalf("Failed to parse JWKS: %v", err)
+		}
+
+		keys, err := jwks.TrustedKeys()
+		if err != nil || len(keys) != 0 {
+			t.Errorf("Expected all keys to be skipped, got %d keys (%v)", len(keys), err)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		jwk := decodeJWK(t, must(t, newIssuer.ExportPublicKeyJWK))
+		jwk.Y = jwk.X
+
+		if _, err := jwk.PublicKey(); !errors.Is(err, licenser.ErrInvalidJWK) {
+			t.Errorf("Expected ErrInvalidJWK for a point off the curve, got %v", err)
+		}
+
+		if _, err := licenser.ParseJWKS([]byte(`{"kty":"OKP"}`)); !errors.Is(err, licenser.ErrInvalidJWK) {
+			t.Errorf("Expected ErrInvalidJWK for a single key, got %v", err)
+		}
+	})
+}
+
+// must returns the result of fn, failing the test on error.
+func must(t *testing.T, fn func() (string, error)) string {
+	t.Helper()
+
+	value, err := fn()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	return value
+}
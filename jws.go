@@ -0,0 +1,290 @@
+/*******************************************************************
+
+		::          ::        +--------+-----------------------+
+		  ::      ::          | Author | Dmitry Novikov        |
+		::::::::::::::        | Email  | dredfort.42@gmail.com |
+	  ::::  ::::::  ::::      +--------+-----------------------+
+	::::::::::::::::::::::
+	::  ::::::::::::::  ::    File     | jws.go
+	::  ::          ::  ::    Created  | 2026-10-16
+		  ::::  ::::          Modified | 2026-10-16
+
+	GitHub:   https://github.com/dredfort42
+	LinkedIn: https://linkedin.com/in/novikov-da
+
+*******************************************************************/
+
+package licenser
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// tokenType is the "typ" header of issued license tokens.
+const tokenType = "JWT"
+
+// jwsEncoding is the unpadded base64url encoding used by compact JWS.
+var jwsEncoding = base64.RawURLEncoding.Strict()
+
+// jwsHeader is the protected header of a compact JWS (RFC 7515).
+type jwsHeader struct {
+	Algorithm string   `json:"alg"`
+	KeyID     string   `json:"kid,omitempty"`
+	Type      string   `json:"typ,omitempty"`
+	Critical  []string `json:"crit,omitempty"`
+}
+
+// claimNames maps License fields to the registered JWT claims (RFC 7519)
+// they are carried in. All other fields keep their license names.
+var claimNames = map[string]string{
+	"customer":   "sub",
+	"app_id":     "aud",
+	"issued_at":  "iat",
+	"not_before": "nbf",
+	"expires_at": "exp",
+}
+
+// GenerateToken creates a signed license encoded as a compact JWS
+// (header.payload.signature) that fits in an HTTP header or environment variable.
+func (m *Manager) GenerateToken(license *License) (string, error) {
+	if m.issuer == nil {
+		return "", ErrGeneratorModeRequired
+	}
+
+	return m.issuer.GenerateToken(license)
+}
+
+// GenerateToken creates a signed license encoded as a compact JWS
+// (header.payload.signature) that fits in an HTTP header or environment variable.
+// The signature follows RFC 7518 for the header algorithm, so any JOSE
+// library can verify it.
+func (i *Issuer) GenerateToken(license *License) (string, error) {
+	if err := i.prepareLicense(license); err != nil {
+		return "", err
+	}
+
+	payload, err := encodeClaims(license)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal license: %w", err)
+	}
+
+	header, err := json.Marshal(jwsHeader{Algorithm: i.signer.Algorithm(), KeyID: i.keyID, Type: tokenType})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal token header: %w", err)
+	}
+
+	protected := jwsEncoding.EncodeToString(header)
+
+	signature, err := i.signData([]byte(protected + "." + jwsEncoding.EncodeToString(payload)))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign license: %w", err)
+	}
+
+	return EncodeToken(&SignedLicense{
+		Format:    FormatJWS,
+		Protected: protected,
+		Payload:   base64.StdEncoding.EncodeToString(payload),
+		Signature: signature,
+	})
+}
+
+// EncodeToken returns a JWS-format signed license as a compact JWS. Licenses
+// in other formats are signed over different bytes and have to be issued
+// again with GenerateToken.
+func EncodeToken(signedLicense *SignedLicense) (string, error) {
+	if signedLicense.Format != FormatJWS {
+		return "", fmt.Errorf("%w: %q cannot be encoded as a token", ErrUnsupportedFormat, signedLicense.Format)
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(signedLicense.Payload)
+	if err != nil {
+		return "", fmt.Errorf("%w: payload is not valid base64", ErrInvalidPayload)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(signedLicense.Signature)
+	if err != nil {
+		return "", ErrInvalidSignature
+	}
+
+	return signedLicense.Protected + "." + jwsEncoding.EncodeToString(payload) + "." +
+		jwsEncoding.EncodeToString(signature), nil
+}
+
+// ParseToken decodes a compact JWS license token without validating it.
+func (m *Manager) ParseToken(token string) (*SignedLicense, error) {
+	return m.verifier.ParseToken(token)
+}
+
+// ValidateToken parses and validates a license token in one call.
+func (m *Manager) ValidateToken(token string) (*SignedLicense, *ValidationResult, error) {
+	return m.verifier.ValidateToken(token)
+}
+
+// ParseToken decodes a compact JWS license token without validating it.
+func (v *Verifier) ParseToken(token string) (*SignedLicense, error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: expected 3 segments, got %d", ErrInvalidToken, len(parts))
+	}
+
+	header, err := decodeJWSHeader(parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := jwsEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed payload", ErrInvalidToken)
+	}
+
+	signature, err := jwsEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidToken)
+	}
+
+	license, err := decodeClaims(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SignedLicense{
+		Data:      *license,
+		Format:    FormatJWS,
+		Protected: parts[0],
+		Payload:   base64.StdEncoding.EncodeToString(payload),
+		Signature: base64.StdEncoding.EncodeToString(signature),
+		KeyID:     header.KeyID,
+		Algorithm: header.Algorithm,
+		CreatedAt: license.IssuedAt,
+	}, nil
+}
+
+// ValidateToken parses and validates a license token in one call.
+func (v *Verifier) ValidateToken(token string) (*SignedLicense, *ValidationResult, error) {
+	signedLicense, err := v.ParseToken(token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := v.ValidateLicense(signedLicense)
+
+	return signedLicense, result, nil
+}
+
+// decodeJWSPayload returns the JWS signing input of a JWS-format license. The
+// algorithm and key ID must match the protected header, which the signature covers.
+func decodeJWSPayload(signedLicense *SignedLicense) ([]byte, *License, error) {
+	header, err := decodeJWSHeader(signedLicense.Protected)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if header.Algorithm != signedLicense.Algorithm || header.KeyID != signedLicense.KeyID {
+		return nil, nil, fmt.Errorf("%w: algorithm or key ID differs from the protected header", ErrPayloadMismatch)
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(signedLicense.Payload)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: payload is not valid base64", ErrInvalidPayload)
+	}
+
+	license, err := decodeClaims(payload)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return []byte(signedLicense.Protected + "." + jwsEncoding.EncodeToString(payload)), license, nil
+}
+
+func decodeJWSHeader(protected string) (*jwsHeader, error) {
+	data, err := jwsEncoding.DecodeString(protected)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed header", ErrInvalidToken)
+	}
+
+	var header jwsHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("%w: malformed header: %w", ErrInvalidToken, err)
+	}
+
+	// No header extensions are understood, so any critical one must be rejected.
+	if len(header.Critical) > 0 {
+		return nil, fmt.Errorf("%w: unsupported critical headers %v", ErrInvalidToken, header.Critical)
+	}
+
+	return &header, nil
+}
+
+// encodeClaims marshals license as a JWT claims set.
+func encodeClaims(license *License) ([]byte, error) {
+	data, err := json.Marshal(license)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims map[string]json.RawMessage
+	if err := json.Unmarshal(data, &claims); err != nil {
+		return nil, err
+	}
+
+	for field, claim := range claimNames {
+		if _, ok := license.Extensions[claim]; ok {
+			return nil, fmt.Errorf("license extension %q conflicts with a registered claim", claim)
+		}
+
+		if value, ok := claims[field]; ok {
+			claims[claim] = value
+			delete(claims, field)
+		}
+	}
+
+	return json.Marshal(claims)
+}
+
+// decodeClaims maps a JWT claims set back to a license. Claims other than the
+// mapped and license ones, such as "iss" or "jti", end up in Extensions.
+func decodeClaims(payload []byte) (*License, error) {
+	var claims map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("%w: malformed claims: %w", ErrInvalidToken, err)
+	}
+
+	for name := range claims {
+		if _, ok := claimNames[strings.ToLower(name)]; ok {
+			return nil, fmt.Errorf("%w: license field %q must be sent as a registered claim", ErrInvalidToken, name)
+		}
+	}
+
+	for field, claim := range claimNames {
+		if value, ok := claims[claim]; ok {
+			claims[field] = value
+			delete(claims, claim)
+		}
+	}
+
+	// "aud" may also be an array; a license targets exactly one application.
+	if audience := bytes.TrimSpace(claims["app_id"]); len(audience) > 0 && audience[0] == '[' {
+		var audiences []string
+		if err := json.Unmarshal(audience, &audiences); err != nil || len(audiences) != 1 {
+			return nil, fmt.Errorf("%w: \"aud\" must name a single application", ErrInvalidToken)
+		}
+
+		claims["app_id"], _ = json.Marshal(audiences[0])
+	}
+
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	var license License
+	if err := json.Unmarshal(data, &license); err != nil {
+		return nil, fmt.Errorf("%w: malformed claims: %w", ErrInvalidToken, err)
+	}
+
+	return &license, nil
+}
@@ -0,0 +1,247 @@
+/*******************************************************************
+
+		::          ::        +--------+-----------------------+
+		  ::      ::          | Author | Dmitry Novikov        |
+		::::::::::::::        | Email  | dredfort.42@gmail.com |
+	  ::::  ::::::  ::::      +--------+-----------------------+
+	::::::::::::::::::::::
+	::  ::::::::::::::  ::    File     | jws_test.go
+	::  ::          ::  ::    Created  | 2026-10-16
+		  ::::  ::::          Modified | 2026-10-16
+
+	GitHub:   https://github.com/dredfort42
+	LinkedIn: https://linkedin.com/in/novikov-da
+
+*******************************************************************/
+
+package licenser_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	licenser "github.com/dredfort42/go_licenser"
+)
+
+// signToken builds a compact JWS from raw header and claims JSON.
+func signToken(t *testing.T, signer licenser.Signer, header, claims string) string {
+	t.Helper()
+
+	input := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(claims))
+
+	signature, err := signer.Sign([]byte(input))
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestToken(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	signer, err := licenser.NewSigner(key, licenser.AlgorithmEdDSA)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+
+	manager, err := licenser.NewManager(licenser.Config{GeneratorMode: true, Signer: signer})
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	license := licenser.License{
+		Customer:  "Token Customer",
+		AppID:     "token-app",
+		Services:  []licenser.Service{{ID: "test", Name: "Test"}},
+		Features:  map[string]bool{"export": true},
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	}
+
+	token, err := manager.GenerateToken(&license)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	t.Run("CompactJWS", func(t *testing.T) {
+		parts := strings.Split(token, ".")
+		if len(parts) != 3 {
+			t.Fatalf("Expected 3 token segments, got %d", len(parts))
+		}
+
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		if err != nil {
+			t.Fatalf("Failed to decode signature: %v", err)
+		}
+
+		publicKey, ok := manager.PublicKey().(ed25519.PublicKey)
+		if !ok || !ed25519.Verify(publicKey, []byte(parts[0]+"."+parts[1]), signature) {
+			t.Error("Token signature should verify as a standard EdDSA JWS")
+		}
+
+		payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+		if err != nil {
+			t.Fatalf("Failed to decode payload: %v", err)
+		}
+
+		var claims map[string]any
+		if err := json.Unmarshal(payload, &claims); err != nil {
+			t.Fatalf("Failed to unmarshal claims: %v", err)
+		}
+
+		if claims["sub"] != license.Customer || claims["aud"] != license.AppID {
+			t.Errorf("Expected sub and aud claims, got %v", claims)
+		}
+
+		if claims["iat"] != float64(license.IssuedAt) || claims["exp"] != float64(license.ExpiresAt) {
+			t.Errorf("Expected iat and exp claims, got %v", claims)
+		}
+
+		if _, ok := claims["customer"]; ok {
+			t.Error("Customer should only be carried in the sub claim")
+		}
+	})
+
+	t.Run("ValidateToken", func(t *testing.T) {
+		signedLicense, result, err := manager.ValidateToken(token)
+		if err != nil {
+			t.Fatalf("Failed to parse token: %v", err)
+		}
+
+		if !result.Valid {
+			t.Errorf("Token should be valid, errors: %v", result.Errors)
+		}
+
+		if signedLicense.Data.Customer != license.Customer || !licenser.HasService(&signedLicense.Data, "test") {
+			t.Errorf("Expected license data to be decoded, got %+v", signedLicense.Data)
+		}
+
+		if signedLicense.KeyID != manager.KeyID() || signedLicense.Algorithm != licenser.AlgorithmEdDSA {
+			t.Errorf("Expected key ID and algorithm from the header, got '%s' and '%s'",
+				signedLicense.KeyID, signedLicense.Algorithm)
+		}
+
+		encoded, err := licenser.EncodeToken(signedLicense)
+		if err != nil {
+			t.Fatalf("Failed to encode token: %v", err)
+		}
+
+		if encoded != token {
+			t.Error("Encoding a parsed token should reproduce it")
+		}
+	})
+
+	t.Run("SaveAndLoad", func(t *testing.T) {
+		signedLicense, err := manager.ParseToken(token)
+		if err != nil {
+			t.Fatalf("Failed to parse token: %v", err)
+		}
+
+		licensePath := filepath.Join(t.TempDir(), "license.json")
+		if err := manager.SaveLicense(signedLicense, licensePath); err != nil {
+			t.Fatalf("Failed to save license: %v", err)
+		}
+
+		_, result, err := manager.LoadAndValidateLicense(licensePath)
+		if err != nil {
+			t.Fatalf("Failed to load license: %v", err)
+		}
+
+		if !result.Valid {
+			t.Errorf("Saved token license should be valid, errors: %v", result.Errors)
+		}
+	})
+
+	t.Run("TamperedClaims", func(t *testing.T) {
+		parts := strings.Split(token, ".")
+		parts[1] = base64.RawURLEncoding.EncodeToString(
+			[]byte(`{"sub":"Token Customer","aud":"token-app","iat":1,"services":[{"id":"all","name":"All"}]}`))
+
+		_, result, err := manager.ValidateToken(strings.Join(parts, "."))
+		if err != nil {
+			t.Fatalf("Failed to parse token: %v", err)
+		}
+
+		if result.Valid {
+			t.Error("Token with tampered claims should be invalid")
+		}
+	})
+
+	t.Run("SwappedAlgorithm", func(t *testing.T) {
+		signedLicense, err := manager.ParseToken(token)
+		if err != nil {
+			t.Fatalf("Failed to parse token: %v", err)
+		}
+
+		signedLicense.Algorithm = licenser.AlgorithmRS256
+
+		result := manager.ValidateLicense(signedLicense)
+		if result.Valid || !contains(result.Errors[0], licenser.ErrPayloadMismatch.Error()) {
+			t.Errorf("Expected protected header mismatch, got %v", result.Errors)
+		}
+	})
+
+	t.Run("ForeignToken", func(t *testing.T) {
+		header := `{"alg":"EdDSA","kid":"` + manager.KeyID() + `"}`
+		claims := `{"iss":"billing","sub":"Foreign Customer","aud":["foreign-app"],"iat":1760000000,` +
+			`"services":[{"id":"test","name":"Test"}]}`
+
+		signedLicense, result, err := manager.ValidateToken(signToken(t, signer, header, claims))
+		if err != nil {
+			t.Fatalf("Failed to parse token: %v", err)
+		}
+
+		if !result.Valid {
+			t.Errorf("Foreign token should be valid, errors: %v", result.Errors)
+		}
+
+		if signedLicense.Data.AppID != "foreign-app" {
+			t.Errorf("Expected app ID 'foreign-app', got '%s'", signedLicense.Data.AppID)
+		}
+
+		if string(signedLicense.Data.Extensions["iss"]) != `"billing"` {
+			t.Errorf("Expected 'iss' claim in extensions, got %v", signedLicense.Data.Extensions)
+		}
+	})
+
+	t.Run("InvalidTokens", func(t *testing.T) {
+		tokens := map[string]string{
+			"Segments":       "a.b",
+			"Header":         "!!.e30.AA",
+			"Critical":       signToken(t, signer, `{"alg":"EdDSA","crit":["exp"]}`, `{}`),
+			"LicenseField":   signToken(t, signer, `{"alg":"EdDSA"}`, `{"customer":"A"}`),
+			"MultiAudience":  signToken(t, signer, `{"alg":"EdDSA"}`, `{"aud":["a","b"]}`),
+			"MalformedClaim": signToken(t, signer, `{"alg":"EdDSA"}`, `[]`),
+		}
+
+		for name, token := range tokens {
+			t.Run(name, func(t *testing.T) {
+				if _, err := manager.ParseToken(token); !errors.Is(err, licenser.ErrInvalidToken) {
+					t.Errorf("Expected ErrInvalidToken, got %v", err)
+				}
+			})
+		}
+	})
+
+	t.Run("EncodeJSONLicense", func(t *testing.T) {
+		signedLicense, err := manager.GenerateLicense(&license)
+		if err != nil {
+			t.Fatalf("Failed to generate license: %v", err)
+		}
+
+		if _, err := licenser.EncodeToken(signedLicense); !errors.Is(err, licenser.ErrUnsupportedFormat) {
+			t.Errorf("Expected ErrUnsupportedFormat, got %v", err)
+		}
+	})
+}
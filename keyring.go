@@ -0,0 +1,375 @@
+/*******************************************************************
+
+		::          ::        +--------+-----------------------+
+		  ::      ::          | Author | Dmitry Novikov        |
+		::::::::::::::        | Email  | dredfort.42@gmail.com |
+	  ::::  ::::::  ::::      +--------+-----------------------+
+	::::::::::::::::::::::
+	::  ::::::::::::::  ::    File     | keyring.go
+	::  ::          ::  ::    Created  | 2026-10-16
+		  ::::  ::::          Modified | 2026-10-16
+
+	GitHub:   https://github.com/dredfort42
+	LinkedIn: https://linkedin.com/in/novikov-da
+
+*******************************************************************/
+
+package licenser
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+	"time"
+)
+
+// fingerprintSize is the number of digest bytes kept in a key fingerprint.
+const fingerprintSize = 8
+
+// TrustedKey describes an additional public key accepted during validation.
+type TrustedKey struct {
+	KeyID         string           `json:"key_id,omitempty"`          // Key identifier (default: key fingerprint)
+	PublicKeyPEM  string           `json:"public_key_pem,omitempty"`  // PEM-encoded public key
+	PublicKeyPath string           `json:"public_key_path,omitempty"` // Path to the public key file
+	PublicKey     crypto.PublicKey `json:"-"`                         // Parsed public key, overrides PEM and path
+	RetiresAt     int64            `json:"retires_at,omitempty"`      // Licenses issued after this timestamp are rejected
+	// Only algorithm accepted for this key (default: any matching the key)
+	Algorithm string `json:"algorithm,omitempty"`
+}
+
+// verificationKey is a public key registered in the manager's key ring.
+type verificationKey struct {
+	id        string
+	publicKey crypto.PublicKey
+	verifier  SignatureVerifier // Custom verifier bound to this key, if any
+	algorithm string            // Only algorithm accepted for this key, if restricted
+	retiresAt int64             // Retirement timestamp, zero if the key is not scheduled for retirement
+}
+
+// KeyFingerprint returns a short hex identifier derived from the SHA-256
+// digest of the PKIX-encoded public key.
+func KeyFingerprint(publicKey crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidPublicKey, err)
+	}
+
+	digest := sha256.Sum256(der)
+
+	return hex.EncodeToString(digest[:fingerprintSize]), nil
+}
+
+// KeyFingerprintSHA256 returns the full SHA-256 digest of the PKIX-encoded
+// public key as colon-separated hex, for showing users which key is trusted.
+// It matches "openssl pkey -pubin -outform DER | openssl dgst -sha256 -c".
+func KeyFingerprintSHA256(publicKey crypto.PublicKey) (string, error) {
+	if publicKey == nil {
+		return "", ErrNoPublicKey
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidPublicKey, err)
+	}
+
+	digest := sha256.Sum256(der)
+	parts := make([]string, len(digest))
+
+	for i, b := range digest {
+		parts[i] = hex.EncodeToString([]byte{b})
+	}
+
+	return strings.Join(parts, ":"), nil
+}
+
+// Fingerprint returns the SHA-256 fingerprint of the manager's public key.
+func (m *Manager) Fingerprint() (string, error) {
+	return m.verifier.Fingerprint()
+}
+
+// Fingerprint returns the SHA-256 fingerprint of the signing key.
+func (i *Issuer) Fingerprint() (string, error) {
+	return KeyFingerprintSHA256(i.signer.Public())
+}
+
+// Fingerprint returns the SHA-256 fingerprint of the verifier's public key.
+func (v *Verifier) Fingerprint() (string, error) {
+	return KeyFingerprintSHA256(v.publicKey)
+}
+
+// KeyID returns the identifier of the manager's own key. In generator mode
+// this is the ID stamped on issued licenses.
+func (m *Manager) KeyID() string {
+	if m.issuer != nil {
+		return m.issuer.KeyID()
+	}
+
+	return m.verifier.KeyID()
+}
+
+// KeyID returns the identifier of the verifier's primary key.
+func (v *Verifier) KeyID() string {
+	if v.primaryKey != nil {
+		return v.primaryKey.id
+	}
+
+	return ""
+}
+
+// TrustedKeyIDs returns the sorted identifiers of all keys accepted during validation.
+func (m *Manager) TrustedKeyIDs() []string {
+	return m.verifier.TrustedKeyIDs()
+}
+
+// TrustedKeyIDs returns the sorted identifiers of all keys accepted during validation.
+func (v *Verifier) TrustedKeyIDs() []string {
+	ids := make([]string, 0, len(v.keys))
+	for id := range v.keys {
+		ids = append(ids, id)
+	}
+
+	slices.Sort(ids)
+
+	return ids
+}
+
+// setupKeyRing registers the primary public key and any configured trusted keys.
+func (v *Verifier) setupKeyRing() error {
+	v.keys = make(map[string]*verificationKey)
+
+	if v.publicKey != nil || v.config.Verifier != nil {
+		id := v.config.KeyID
+		if id == "" && v.publicKey != nil {
+			var err error
+
+			id, err = KeyFingerprint(v.publicKey)
+			if err != nil {
+				return err
+			}
+		}
+
+		v.primaryKey = &verificationKey{
+			id:        id,
+			publicKey: v.publicKey,
+			verifier:  v.config.Verifier,
+			retiresAt: v.config.KeyRetiresAt,
+		}
+
+		if id != "" {
+			v.keys[id] = v.primaryKey
+		}
+	}
+
+	for i, trusted := range v.config.TrustedKeys {
+		if err := v.addTrustedKey(trusted); err != nil {
+			return fmt.Errorf("failed to load trusted key %d: %w", i, err)
+		}
+	}
+
+	if v.config.TrustedKeysJWKSPath == "" {
+		return nil
+	}
+
+	data, err := readFile(v.config.FS, v.config.TrustedKeysJWKSPath)
+	if err != nil {
+		return fmt.Errorf("failed to read JWKS: %w", err)
+	}
+
+	jwks, err := ParseJWKS(data)
+	if err != nil {
+		return err
+	}
+
+	trustedKeys, err := jwks.TrustedKeys()
+	if err != nil {
+		return err
+	}
+
+	for _, trusted := range trustedKeys {
+		if err := v.addTrustedKey(trusted); err != nil {
+			return fmt.Errorf("failed to load JWKS key %s: %w", trusted.KeyID, err)
+		}
+	}
+
+	return nil
+}
+
+// addTrustedKey registers a trusted key. Registering the same key twice is
+// allowed; reusing a key ID for a different key is not.
+func (v *Verifier) addTrustedKey(trusted TrustedKey) error {
+	key, err := loadTrustedKey(v.config.FS, trusted)
+	if err != nil {
+		return err
+	}
+
+	if existing, ok := v.keys[key.id]; ok {
+		if !sameKey(existing.publicKey, key.publicKey) {
+			return fmt.Errorf("%w: %s", ErrDuplicateKeyID, key.id)
+		}
+
+		return nil
+	}
+
+	v.keys[key.id] = key
+
+	return nil
+}
+
+// lookupKey finds the verification key for a license. Licenses without a key
+// ID predate key rings and are verified with the primary key.
+func (v *Verifier) lookupKey(keyID string) (*verificationKey, error) {
+	if keyID == "" {
+		if v.primaryKey == nil {
+			return nil, fmt.Errorf("%w: license does not declare a key ID", ErrUnknownKeyID)
+		}
+
+		return v.primaryKey, nil
+	}
+
+	if key, ok := v.keys[keyID]; ok {
+		return key, nil
+	}
+
+	// A custom verifier configured without a key ID has no identity to match
+	// against, so it is tried for any key ID and the signature decides.
+	if v.primaryKey != nil && v.primaryKey.id == "" {
+		return v.primaryKey, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnknownKeyID, keyID)
+}
+
+func loadTrustedKey(fsys fs.FS, trusted TrustedKey) (*verificationKey, error) {
+	publicKey := trusted.PublicKey
+
+	var err error
+
+	switch {
+	case publicKey != nil:
+	case trusted.PublicKeyPEM != "":
+		publicKey, err = parsePublicKeyFromPEM(trusted.PublicKeyPEM)
+	case trusted.PublicKeyPath != "":
+		publicKey, err = loadPublicKeyFromFile(fsys, trusted.PublicKeyPath)
+	default:
+		err = ErrNoPublicKey
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if trusted.Algorithm != "" {
+		if err := checkKeyAlgorithm(publicKey, trusted.Algorithm); err != nil {
+			return nil, err
+		}
+	}
+
+	id := trusted.KeyID
+	if id == "" {
+		id, err = KeyFingerprint(publicKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &verificationKey{
+		id:        id,
+		publicKey: publicKey,
+		algorithm: trusted.Algorithm,
+		retiresAt: trusted.RetiresAt,
+	}, nil
+}
+
+// checkAlgorithm reports whether the declared algorithm can be used with the key.
+func (k *verificationKey) checkAlgorithm(algorithm string) error {
+	if k.verifier != nil {
+		if algorithm != k.verifier.Algorithm() {
+			return fmt.Errorf("%w: %s", ErrAlgorithmMismatch, algorithm)
+		}
+
+		return nil
+	}
+
+	if k.algorithm != "" && algorithm != k.algorithm {
+		return fmt.Errorf("%w: %s", ErrAlgorithmMismatch, algorithm)
+	}
+
+	if err := checkKeyAlgorithm(k.publicKey, algorithm); err != nil {
+		return fmt.Errorf("%w: %s", ErrAlgorithmMismatch, algorithm)
+	}
+
+	return nil
+}
+
+// verify checks signature over data with the algorithm the license was issued
+// with, so RS256 licenses keep validating after the issuer moves to PS256.
+func (k *verificationKey) verify(data, signature []byte, algorithm string) error {
+	verifier := k.verifier
+	if verifier == nil {
+		var err error
+
+		verifier, err = NewSignatureVerifier(k.publicKey, algorithm)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := verifier.Verify(data, signature); err != nil {
+		return ErrSignatureVerification
+	}
+
+	return nil
+}
+
+// checkRetirement rejects licenses issued after the key was retired and warns
+// about licenses signed by a key that is retired or scheduled for retirement
+// as of at.
+func (k *verificationKey) checkRetirement(license *License, at time.Time, result *ValidationResult) {
+	if k.retiresAt == 0 {
+		return
+	}
+
+	retiresAt := time.Unix(k.retiresAt, 0).Format(TimestampLayout)
+
+	if license.IssuedAt > k.retiresAt {
+		err := fmt.Errorf("%w: %s was retired on %s", ErrKeyRetired, k.id, retiresAt)
+		result.addError(IssueKeyRetired, "key_id", err, err.Error())
+
+		return
+	}
+
+	if at.Unix() > k.retiresAt {
+		result.addWarning(IssueKeyRetiring, "key_id", nil,
+			fmt.Sprintf("license is signed by key %s, which was retired on %s", k.id, retiresAt))
+
+		return
+	}
+
+	result.addWarning(IssueKeyRetiring, "key_id", nil,
+		fmt.Sprintf("license is signed by key %s scheduled for retirement on %s", k.id, retiresAt))
+}
+
+// checkKeySize warns about licenses verified with an RSA key shorter than
+// MinRSAKeySize.
+func (k *verificationKey) checkKeySize(result *ValidationResult) {
+	rsaKey, ok := k.publicKey.(*rsa.PublicKey)
+	if !ok || rsaKey.N.BitLen() >= MinRSAKeySize {
+		return
+	}
+
+	result.addWarning(IssueWeakKey, "key_id", nil,
+		fmt.Sprintf("license is signed by a %d-bit RSA key, %d bits or more are recommended",
+			rsaKey.N.BitLen(), MinRSAKeySize))
+}
+
+func sameKey(a, b crypto.PublicKey) bool {
+	key, ok := a.(interface{ Equal(x crypto.PublicKey) bool })
+
+	return ok && key.Equal(b)
+}
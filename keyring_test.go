@@ -0,0 +1,282 @@
+/*******************************************************************
+
+		::          ::        +--------+-----------------------+
+		  ::      ::          | Author | Dmitry Novikov        |
+		::::::::::::::        | Email  | dredfort.42@gmail.com |
+	  ::::  ::::::  ::::      +--------+-----------------------+
+	::::::::::::::::::::::
+	::  ::::::::::::::  ::    File     | keyring_test.go
+	::  ::          ::  ::    Created  | 2026-10-16
+		  ::::  ::::          Modified | 2026-10-16
+
+	GitHub:   https://github.com/dredfort42
+	LinkedIn: https://linkedin.com/in/novikov-da
+
+*******************************************************************/
+
+package licenser_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	licenser "github.com/dredfort42/go_licenser"
+)
+
+func newEd25519Manager(t *testing.T, config licenser.Config) *licenser.Manager {
+	t.Helper()
+
+	config.GeneratorMode = true
+	config.Algorithm = licenser.AlgorithmEdDSA
+
+	manager, err := licenser.NewManager(config)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	return manager
+}
+
+// exportPublicKey exports a public key PEM, failing the test on error.
+func exportPublicKey(t *testing.T, exporter interface{ ExportPublicKey() (string, error) }) string {
+	t.Helper()
+
+	publicKeyPEM, err := exporter.ExportPublicKey()
+	if err != nil {
+		t.Fatalf("Failed to export public key: %v", err)
+	}
+
+	return publicKeyPEM
+}
+
+// exportPrivateKey exports a private key PEM, failing the test on error.
+func exportPrivateKey(t *testing.T, exporter interface{ ExportPrivateKey() (string, error) }) string {
+	t.Helper()
+
+	privateKeyPEM, err := exporter.ExportPrivateKey()
+	if err != nil {
+		t.Fatalf("Failed to export private key: %v", err)
+	}
+
+	return privateKeyPEM
+}
+
+func TestKeyRing(t *testing.T) {
+	license := licenser.License{
+		Customer: "Key Ring Customer",
+		AppID:    "key-ring-app",
+		Services: []licenser.Service{{ID: "test", Name: "Test"}},
+	}
+
+	oldManager := newEd25519Manager(t, licenser.Config{})
+
+	oldLicense, err := oldManager.GenerateLicense(&license)
+	if err != nil {
+		t.Fatalf("Failed to generate license: %v", err)
+	}
+
+	newManager := newEd25519Manager(t, licenser.Config{
+		TrustedKeys: []licenser.TrustedKey{{PublicKeyPEM: exportPublicKey(t, oldManager)}},
+	})
+
+	newLicense, err := newManager.GenerateLicense(&license)
+	if err != nil {
+		t.Fatalf("Failed to generate license: %v", err)
+	}
+
+	t.Run("KeyIDIsFingerprint", func(t *testing.T) {
+		fingerprint, err := licenser.KeyFingerprint(oldManager.PublicKey())
+		if err != nil {
+			t.Fatalf("Failed to fingerprint key: %v", err)
+		}
+
+		if oldLicense.KeyID != fingerprint {
+			t.Errorf("Expected key ID '%s', got '%s'", fingerprint, oldLicense.KeyID)
+		}
+
+		if oldManager.KeyID() != fingerprint {
+			t.Errorf("Expected manager key ID '%s', got '%s'", fingerprint, oldManager.KeyID())
+		}
+
+		if newLicense.KeyID == oldLicense.KeyID {
+			t.Error("Different keys should have different key IDs")
+		}
+	})
+
+	t.Run("ValidateAcrossRotation", func(t *testing.T) {
+		for name, signedLicense := range map[string]*licenser.SignedLicense{"old": oldLicense, "new": newLicense} {
+			result := newManager.ValidateLicense(signedLicense)
+			if !result.Valid {
+				t.Errorf("License signed by %s key should be valid, errors: %v", name, result.Errors)
+			}
+		}
+
+		if len(newManager.TrustedKeyIDs()) != 2 {
+			t.Errorf("Expected 2 trusted keys, got %v", newManager.TrustedKeyIDs())
+		}
+	})
+
+	t.Run("UnknownKeyID", func(t *testing.T) {
+		result := oldManager.ValidateLicense(newLicense)
+		if result.Valid {
+			t.Error("License signed by an untrusted key should be invalid")
+		}
+
+		if len(result.Errors) == 0 || !contains(result.Errors[0], licenser.ErrUnknownKeyID.Error()) {
+			t.Errorf("Expected unknown key ID error, got %v", result.Errors)
+		}
+	})
+
+	t.Run("SwappedKeyID", func(t *testing.T) {
+		forged := *newLicense
+		forged.KeyID = oldLicense.KeyID
+
+		if newManager.ValidateLicense(&forged).Valid {
+			t.Error("License with a swapped key ID should be invalid")
+		}
+	})
+
+	t.Run("CustomKeyID", func(t *testing.T) {
+		manager := newEd25519Manager(t, licenser.Config{KeyID: "2026-q3"})
+
+		signedLicense, err := manager.GenerateLicense(&license)
+		if err != nil {
+			t.Fatalf("Failed to generate license: %v", err)
+		}
+
+		if signedLicense.KeyID != "2026-q3" {
+			t.Errorf("Expected key ID '2026-q3', got '%s'", signedLicense.KeyID)
+		}
+
+		validator, err := licenser.NewManager(licenser.Config{
+			TrustedKeys: []licenser.TrustedKey{
+				{KeyID: "2026-q3", PublicKey: manager.PublicKey()},
+				{PublicKey: oldManager.PublicKey()},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Failed to create validator from trusted keys: %v", err)
+		}
+
+		for _, signed := range []*licenser.SignedLicense{signedLicense, oldLicense} {
+			result := validator.ValidateLicense(signed)
+			if !result.Valid {
+				t.Errorf("License with key ID '%s' should be valid, errors: %v", signed.KeyID, result.Errors)
+			}
+		}
+	})
+
+	t.Run("DuplicateKeyID", func(t *testing.T) {
+		_, err := licenser.NewManager(licenser.Config{
+			TrustedKeys: []licenser.TrustedKey{
+				{KeyID: "shared", PublicKey: oldManager.PublicKey()},
+				{KeyID: "shared", PublicKey: newManager.PublicKey()},
+			},
+		})
+		if !errors.Is(err, licenser.ErrDuplicateKeyID) {
+			t.Errorf("Expected ErrDuplicateKeyID, got %v", err)
+		}
+	})
+}
+
+func TestKeyRetirement(t *testing.T) {
+	retiresAt := time.Now().Add(-24 * time.Hour)
+
+	oldManager := newEd25519Manager(t, licenser.Config{})
+
+	validator, err := licenser.NewManager(licenser.Config{
+		TrustedKeys: []licenser.TrustedKey{
+			{PublicKey: oldManager.PublicKey(), RetiresAt: retiresAt.Unix()},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+
+	issue := func(t *testing.T, issuedAt time.Time) *licenser.SignedLicense {
+		t.Helper()
+
+		license := licenser.License{
+			Customer: "Retirement Customer",
+			AppID:    "retirement-app",
+			Services: []licenser.Service{{ID: "test", Name: "Test"}},
+			IssuedAt: issuedAt.Unix(),
+		}
+
+		signedLicense, err := oldManager.GenerateLicense(&license)
+		if err != nil {
+			t.Fatalf("Failed to generate license: %v", err)
+		}
+
+		return signedLicense
+	}
+
+	t.Run("IssuedBeforeRetirement", func(t *testing.T) {
+		result := validator.ValidateLicense(issue(t, retiresAt.Add(-time.Hour)))
+		if !result.Valid {
+			t.Errorf("License issued before retirement should be valid, errors: %v", result.Errors)
+		}
+
+		if len(result.Warnings) == 0 || !contains(result.Warnings[0], "was retired on") {
+			t.Errorf("Expected a retired key warning, got %v", result.Warnings)
+		}
+	})
+
+	t.Run("IssuedAfterRetirement", func(t *testing.T) {
+		result := validator.ValidateLicense(issue(t, retiresAt.Add(time.Hour)))
+		if result.Valid {
+			t.Error("License issued after retirement should be invalid")
+		}
+
+		if len(result.Errors) == 0 || !contains(result.Errors[0], licenser.ErrKeyRetired.Error()) {
+			t.Errorf("Expected key retired error, got %v", result.Errors)
+		}
+	})
+
+	t.Run("ScheduledRetirement", func(t *testing.T) {
+		manager := newEd25519Manager(t, licenser.Config{KeyRetiresAt: time.Now().Add(30 * 24 * time.Hour).Unix()})
+
+		license := licenser.License{
+			Customer: "Retirement Customer",
+			AppID:    "retirement-app",
+			Services: []licenser.Service{{ID: "test", Name: "Test"}},
+			Version:  "1.0.0",
+		}
+
+		signedLicense, err := manager.GenerateLicense(&license)
+		if err != nil {
+			t.Fatalf("Failed to generate license: %v", err)
+		}
+
+		result := manager.ValidateLicense(signedLicense)
+		if !result.Valid {
+			t.Errorf("License should be valid, errors: %v", result.Errors)
+		}
+
+		if len(result.Warnings) != 1 || !contains(result.Warnings[0], "scheduled for retirement") {
+			t.Errorf("Expected one retirement warning, got %v", result.Warnings)
+		}
+
+		// Audited after the retirement date, the key is reported as retired.
+		later := manager.ValidateLicenseAt(signedLicense, time.Now().Add(31*24*time.Hour))
+		if len(later.Warnings) != 1 || !contains(later.Warnings[0], "was retired on") {
+			t.Errorf("Expected a retired key warning, got %v", later.Warnings)
+		}
+	})
+
+	t.Run("IssueWithRetiredKey", func(t *testing.T) {
+		manager := newEd25519Manager(t, licenser.Config{KeyRetiresAt: retiresAt.Unix()})
+
+		license := licenser.License{
+			Customer: "Retirement Customer",
+			AppID:    "retirement-app",
+			Services: []licenser.Service{{ID: "test", Name: "Test"}},
+		}
+
+		_, err := manager.GenerateLicense(&license)
+		if !errors.Is(err, licenser.ErrKeyRetired) {
+			t.Errorf("Expected ErrKeyRetired, got %v", err)
+		}
+	})
+}
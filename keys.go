@@ -0,0 +1,256 @@
+/*******************************************************************
+
+		::          ::        +--------+-----------------------+
+		  ::      ::          | Author | Dmitry Novikov        |
+		::::::::::::::        | Email  | dredfort.42@gmail.com |
+	  ::::  ::::::  ::::      +--------+-----------------------+
+	::::::::::::::::::::::
+	::  ::::::::::::::  ::    File     | keys.go
+	::  ::          ::  ::    Created  | 2026-10-16
+		  ::::  ::::          Modified | 2026-10-16
+
+	GitHub:   https://github.com/dredfort42
+	LinkedIn: https://linkedin.com/in/novikov-da
+
+*******************************************************************/
+
+package licenser
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/fs"
+)
+
+// PEM block types used for keys.
+const (
+	pemTypeRSAPrivateKey  = "RSA PRIVATE KEY"
+	pemTypeECPrivateKey   = "EC PRIVATE KEY"
+	pemTypePrivateKey     = "PRIVATE KEY"
+	pemTypeEncryptedKey   = "ENCRYPTED PRIVATE KEY"
+	pemTypePublicKey      = "PUBLIC KEY"
+	pemTypeRSAPublicKey   = "RSA PUBLIC KEY"
+	pemTypeECParameters   = "EC PARAMETERS"
+	pemTypeCertificate    = "CERTIFICATE"
+	pemTypeOpenSSHPrivKey = "OPENSSH PRIVATE KEY"
+)
+
+// passphraseFunc supplies the passphrase of an encrypted private key.
+type passphraseFunc func() (string, error)
+
+func generateKey(algorithm string, keySize int) (crypto.Signer, error) {
+	switch algorithm {
+	case "", AlgorithmRS256, AlgorithmPS256:
+		return rsa.GenerateKey(rand.Reader, keySize)
+	case AlgorithmEdDSA:
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+
+		return privateKey, err
+	case AlgorithmES256, AlgorithmES384:
+		return ecdsa.GenerateKey(curveForAlgorithm(algorithm), rand.Reader)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, algorithm)
+	}
+}
+
+// algorithmForKey returns the default signing algorithm for a key type.
+func algorithmForKey(publicKey crypto.PublicKey) string {
+	switch pub := publicKey.(type) {
+	case ed25519.PublicKey:
+		return AlgorithmEdDSA
+	case *ecdsa.PublicKey:
+		if pub.Curve == elliptic.P384() {
+			return AlgorithmES384
+		}
+
+		return AlgorithmES256
+	default:
+		return DefaultAlgorithm
+	}
+}
+
+// marshalPrivateKey encodes key in its traditional form (PKCS#1 for RSA,
+// SEC1 for ECDSA) or as PKCS#8. Ed25519 keys are always PKCS#8.
+func marshalPrivateKey(key crypto.Signer, pkcs8 bool) (*pem.Block, error) {
+	if !pkcs8 {
+		switch key := key.(type) {
+		case *rsa.PrivateKey:
+			return &pem.Block{Type: pemTypeRSAPrivateKey, Bytes: x509.MarshalPKCS1PrivateKey(key)}, nil
+		case *ecdsa.PrivateKey:
+			der, err := x509.MarshalECPrivateKey(key)
+			if err != nil {
+				return nil, err
+			}
+
+			return &pem.Block{Type: pemTypeECPrivateKey, Bytes: der}, nil
+		}
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pem.Block{Type: pemTypePrivateKey, Bytes: der}, nil
+}
+
+// marshalEncryptedPrivateKey encodes key as a passphrase-protected PKCS#8
+// "ENCRYPTED PRIVATE KEY" block.
+func marshalEncryptedPrivateKey(key crypto.Signer, passphrase string) (*pem.Block, error) {
+	if passphrase == "" {
+		return nil, ErrPassphraseRequired
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := encryptPKCS8(der, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt private key: %w", err)
+	}
+
+	return &pem.Block{Type: pemTypeEncryptedKey, Bytes: encrypted}, nil
+}
+
+func loadPrivateKeyFromFile(fsys fs.FS, filePath string, passphrase passphraseFunc) (crypto.Signer, error) {
+	data, err := readFile(fsys, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePrivateKeyFromPEM(string(data), passphrase)
+}
+
+func loadPublicKeyFromFile(fsys fs.FS, filePath string) (crypto.PublicKey, error) {
+	data, err := readFile(fsys, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePublicKeyFromPEM(string(data))
+}
+
+// parsePrivateKeyFromPEM parses the first private key in pemData. PKCS#1,
+// PKCS#8 and SEC1 encodings are accepted regardless of the block label, and
+// leading blocks such as the "EC PARAMETERS" written by openssl are skipped.
+// Encrypted PKCS#8 keys are decrypted with the passphrase, which is only
+// requested when such a key is found.
+func parsePrivateKeyFromPEM(pemData string, passphrase passphraseFunc) (crypto.Signer, error) {
+	rest := []byte(pemData)
+
+	for {
+		var block *pem.Block
+
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, fmt.Errorf("%w: no PEM private key block found", ErrInvalidPrivateKey)
+		}
+
+		switch block.Type {
+		case pemTypeECParameters, pemTypePublicKey, pemTypeCertificate:
+			continue
+		case pemTypeOpenSSHPrivKey:
+			return nil, fmt.Errorf("%w: OpenSSH keys are not supported, convert with ssh-keygen -p -m PKCS8",
+				ErrInvalidPrivateKey)
+		case pemTypeEncryptedKey:
+			return parseEncryptedPrivateKey(block.Bytes, passphrase)
+		}
+
+		if _, ok := block.Headers["DEK-Info"]; ok {
+			return nil, fmt.Errorf("%w: legacy PEM encryption is not supported, convert with openssl pkcs8 -topk8",
+				ErrInvalidPrivateKey)
+		}
+
+		return parsePrivateKeyDER(block.Bytes)
+	}
+}
+
+func parseEncryptedPrivateKey(der []byte, passphrase passphraseFunc) (crypto.Signer, error) {
+	if passphrase == nil {
+		return nil, ErrPassphraseRequired
+	}
+
+	secret, err := passphrase()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get passphrase: %w", err)
+	}
+
+	if secret == "" {
+		return nil, ErrPassphraseRequired
+	}
+
+	der, err = decryptPKCS8(der, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := parsePrivateKeyDER(der)
+	if err != nil {
+		// The padding check lets roughly one wrong passphrase in 256 through.
+		return nil, ErrIncorrectPassphrase
+	}
+
+	return key, nil
+}
+
+// parsePrivateKeyDER tries every supported private key encoding in turn.
+func parsePrivateKeyDER(der []byte) (crypto.Signer, error) {
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		switch key := key.(type) {
+		case *rsa.PrivateKey:
+			return key, nil
+		case *ecdsa.PrivateKey:
+			return key, nil
+		case ed25519.PrivateKey:
+			return key, nil
+		default:
+			return nil, fmt.Errorf("%w: unsupported key type %T", ErrInvalidPrivateKey, key)
+		}
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("%w: not a PKCS#1, PKCS#8 or SEC1 key", ErrInvalidPrivateKey)
+}
+
+func parsePublicKeyFromPEM(pemData string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(pemData))
+	if block == nil {
+		return nil, ErrInvalidPublicKey
+	}
+
+	if block.Type == pemTypeRSAPublicKey {
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		return pub, nil
+	case *ecdsa.PublicKey:
+		return pub, nil
+	case ed25519.PublicKey:
+		return pub, nil
+	default:
+		return nil, ErrInvalidPublicKey
+	}
+}
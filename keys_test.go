@@ -0,0 +1,149 @@
+/*******************************************************************
+
+		::          ::        +--------+-----------------------+
+		  ::      ::          | Author | Dmitry Novikov        |
+		::::::::::::::        | Email  | dredfort.42@gmail.com |
+	  ::::  ::::::  ::::      +--------+-----------------------+
+	::::::::::::::::::::::
+	::  ::::::::::::::  ::    File     | keys_test.go
+	::  ::          ::  ::    Created  | 2026-10-16
+		  ::::  ::::          Modified | 2026-10-16
+
+	GitHub:   https://github.com/dredfort42
+	LinkedIn: https://linkedin.com/in/novikov-da
+
+*******************************************************************/
+
+package licenser_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"testing"
+
+	licenser "github.com/dredfort42/go_licenser"
+)
+
+func encodePEM(blockType string, der []byte) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}))
+}
+
+func TestPrivateKeyFormats(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate ECDSA key: %v", err)
+	}
+
+	rsaPKCS8, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal RSA key: %v", err)
+	}
+
+	ecPKCS8, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal ECDSA key: %v", err)
+	}
+
+	ecSEC1, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal ECDSA key: %v", err)
+	}
+
+	// Named curve OID for P-256.
+	ecParams, err := asn1.Marshal(asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7})
+	if err != nil {
+		t.Fatalf("Failed to marshal curve parameters: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		pem       string
+		algorithm string
+	}{
+		{"PKCS1", encodePEM("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)), licenser.AlgorithmRS256},
+		{"PKCS8RSA", encodePEM("PRIVATE KEY", rsaPKCS8), licenser.AlgorithmRS256},
+		{"PKCS8ECDSA", encodePEM("PRIVATE KEY", ecPKCS8), licenser.AlgorithmES256},
+		{"SEC1", encodePEM("EC PRIVATE KEY", ecSEC1), licenser.AlgorithmES256},
+		// openssl ecparam -genkey writes the curve parameters first.
+		{"SEC1WithParameters", encodePEM("EC PARAMETERS", ecParams) + encodePEM("EC PRIVATE KEY", ecSEC1),
+			licenser.AlgorithmES256},
+		{"Mislabelled", encodePEM("RSA PRIVATE KEY", rsaPKCS8), licenser.AlgorithmRS256},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager, err := licenser.NewManager(licenser.Config{
+				PrivateKeyPEM: tt.pem,
+				GeneratorMode: true,
+			})
+			if err != nil {
+				t.Fatalf("Failed to load private key: %v", err)
+			}
+
+			license := licenser.License{
+				Customer: "Key Format Customer",
+				AppID:    "key-format-app",
+				Services: []licenser.Service{{ID: "test", Name: "Test"}},
+			}
+
+			signedLicense, err := manager.GenerateLicense(&license)
+			if err != nil {
+				t.Fatalf("Failed to generate license: %v", err)
+			}
+
+			if signedLicense.Algorithm != tt.algorithm {
+				t.Errorf("Expected algorithm '%s', got '%s'", tt.algorithm, signedLicense.Algorithm)
+			}
+		})
+	}
+
+	t.Run("InvalidKey", func(t *testing.T) {
+		_, err := licenser.NewManager(licenser.Config{
+			PrivateKeyPEM: encodePEM("PRIVATE KEY", []byte("not a key")),
+			GeneratorMode: true,
+		})
+		if !errors.Is(err, licenser.ErrInvalidPrivateKey) {
+			t.Errorf("Expected ErrInvalidPrivateKey, got %v", err)
+		}
+	})
+
+	t.Run("PKCS1PublicKey", func(t *testing.T) {
+		_, err := licenser.NewManager(licenser.Config{
+			PublicKeyPEM: encodePEM("RSA PUBLIC KEY", x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey)),
+		})
+		if err != nil {
+			t.Errorf("Failed to load PKCS#1 public key: %v", err)
+		}
+	})
+
+	t.Run("ExportPKCS8", func(t *testing.T) {
+		manager, err := licenser.NewManager(licenser.Config{
+			PrivateKeyPEM: encodePEM("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)),
+			GeneratorMode: true,
+			PKCS8:         true,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create manager: %v", err)
+		}
+
+		privateKeyPEM := exportPrivateKey(t, manager)
+		if !contains(privateKeyPEM, "BEGIN PRIVATE KEY") {
+			t.Errorf("Expected PKCS#8 private key, got %s", privateKeyPEM)
+		}
+
+		if _, err := licenser.NewManager(licenser.Config{PrivateKeyPEM: privateKeyPEM, GeneratorMode: true}); err != nil {
+			t.Errorf("Failed to reload PKCS#8 private key: %v", err)
+		}
+	})
+}
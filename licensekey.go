@@ -0,0 +1,286 @@
+/*******************************************************************
+
+		::          ::        +--------+-----------------------+
+		  ::      ::          | Author | Dmitry Novikov        |
+		::::::::::::::        | Email  | dredfort.42@gmail.com |
+	  ::::  ::::::  ::::      +--------+-----------------------+
+	::::::::::::::::::::::
+	::  ::::::::::::::  ::    File     | licensekey.go
+	::  ::          ::  ::    Created  | 2026-10-16
+		  ::::  ::::          Modified | 2026-10-16
+
+	GitHub:   https://github.com/dredfort42
+	LinkedIn: https://linkedin.com/in/novikov-da
+
+*******************************************************************/
+
+package licenser
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// A license key is a signed license packed into a single typeable string:
+//
+//	version | algorithm | key ID header | key ID | uvarint len(payload) | payload | signature | CRC-32
+//
+// encoded with Crockford's base32 alphabet and split into dash-separated
+// groups. The payload is the CBOR claims map also used by COSE licenses, and
+// the signature covers it directly. Key IDs written as lower-case hex, such
+// as the default key fingerprints, are stored as raw bytes; the top bit of
+// the key ID header marks them, the low bits hold the stored length. The
+// CRC-32 catches typos before the signature is checked, so a mistyped key is
+// reported as such instead of as a forgery.
+const (
+	licenseKeyVersion   = 1
+	licenseKeyGroupSize = 5
+	licenseKeyHexKeyID  = 0x80 // Key ID header flag for hex key IDs stored as raw bytes
+	licenseKeyAlphabet  = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+// licenseKeyEncoding is Crockford's base32, which avoids I, L, O and U.
+var licenseKeyEncoding = base32.NewEncoding(licenseKeyAlphabet).WithPadding(base32.NoPadding)
+
+// licenseKeyAlgorithms assigns each algorithm its one-byte code in license keys.
+var licenseKeyAlgorithms = []string{
+	1: AlgorithmRS256,
+	2: AlgorithmPS256,
+	3: AlgorithmEdDSA,
+	4: AlgorithmES256,
+	5: AlgorithmES384,
+}
+
+// GenerateLicenseKey creates a signed license encoded as a license key.
+// See README for typical key lengths per algorithm.
+func (m *Manager) GenerateLicenseKey(license *License) (string, error) {
+	if m.issuer == nil {
+		return "", ErrGeneratorModeRequired
+	}
+
+	return m.issuer.GenerateLicenseKey(license)
+}
+
+// GenerateLicenseKey creates a signed license encoded as a license key.
+// See README for typical key lengths per algorithm.
+func (i *Issuer) GenerateLicenseKey(license *License) (string, error) {
+	if err := i.prepareLicense(license); err != nil {
+		return "", err
+	}
+
+	payload, err := encodeCOSEClaims(license)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal license: %w", err)
+	}
+
+	signature, err := i.signData(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign license: %w", err)
+	}
+
+	// Data mirrors the payload exactly, as ParseLicenseKey will decode it.
+	decoded, err := decodeCOSEClaims(payload)
+	if err != nil {
+		return "", err
+	}
+
+	return EncodeLicenseKey(&SignedLicense{
+		Data:      *decoded,
+		Format:    FormatLicenseKey,
+		Payload:   base64.StdEncoding.EncodeToString(payload),
+		Signature: signature,
+		KeyID:     i.keyID,
+		Algorithm: i.signer.Algorithm(),
+		CreatedAt: decoded.IssuedAt,
+	})
+}
+
+// EncodeLicenseKey packs a license-key-format signed license, such as one
+// returned by ParseLicenseKey, into a license key such as "0A1B2-C3D4E-...".
+// Licenses in other formats are signed over different bytes and have to be
+// issued again with GenerateLicenseKey.
+func EncodeLicenseKey(signedLicense *SignedLicense) (string, error) {
+	if signedLicense.Format != FormatLicenseKey {
+		return "", fmt.Errorf("%w: %q cannot be encoded as a license key", ErrUnsupportedFormat, signedLicense.Format)
+	}
+
+	payload, _, err := decodeLicenseKeyPayload(signedLicense)
+	if err != nil {
+		return "", err
+	}
+
+	algorithm := slices.Index(licenseKeyAlgorithms, signedLicense.Algorithm)
+	if algorithm <= 0 {
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, signedLicense.Algorithm)
+	}
+
+	keyID, header := []byte(signedLicense.KeyID), byte(0)
+	if raw, err := hex.DecodeString(signedLicense.KeyID); err == nil && hex.EncodeToString(raw) == signedLicense.KeyID {
+		keyID, header = raw, licenseKeyHexKeyID
+	}
+
+	if len(keyID) >= licenseKeyHexKeyID {
+		return "", fmt.Errorf("%w: key ID is longer than %d bytes", ErrInvalidLicenseKey, licenseKeyHexKeyID-1)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(signedLicense.Signature)
+	if err != nil {
+		return "", ErrInvalidSignature
+	}
+
+	raw := []byte{licenseKeyVersion, byte(algorithm), header | byte(len(keyID))}
+	raw = append(raw, keyID...)
+	raw = binary.AppendUvarint(raw, uint64(len(payload)))
+	raw = append(raw, payload...)
+	raw = append(raw, signature...)
+	raw = binary.BigEndian.AppendUint32(raw, crc32.ChecksumIEEE(raw))
+
+	encoded := licenseKeyEncoding.EncodeToString(raw)
+
+	groups := make([]string, 0, len(encoded)/licenseKeyGroupSize+1)
+	for len(encoded) > licenseKeyGroupSize {
+		groups = append(groups, encoded[:licenseKeyGroupSize])
+		encoded = encoded[licenseKeyGroupSize:]
+	}
+
+	groups = append(groups, encoded)
+
+	return strings.Join(groups, "-"), nil
+}
+
+// ParseLicenseKey unpacks a license key without validating its signature.
+// Dashes, whitespace and case are ignored, and the look-alike letters O, I
+// and L are read as digits. A mistyped key fails with ErrLicenseKeyChecksum.
+func (m *Manager) ParseLicenseKey(key string) (*SignedLicense, error) {
+	return m.verifier.ParseLicenseKey(key)
+}
+
+// ValidateLicenseKey parses and validates a license key in one call.
+func (m *Manager) ValidateLicenseKey(key string) (*SignedLicense, *ValidationResult, error) {
+	return m.verifier.ValidateLicenseKey(key)
+}
+
+// ParseLicenseKey unpacks a license key without validating its signature.
+// Dashes, whitespace and case are ignored, and the look-alike letters O, I
+// and L are read as digits. A mistyped key fails with ErrLicenseKeyChecksum.
+func (v *Verifier) ParseLicenseKey(key string) (*SignedLicense, error) {
+	raw, err := decodeLicenseKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(raw) < 4 || binary.BigEndian.Uint32(raw[len(raw)-4:]) != crc32.ChecksumIEEE(raw[:len(raw)-4]) {
+		return nil, ErrLicenseKeyChecksum
+	}
+
+	raw = raw[:len(raw)-4]
+
+	if len(raw) < 3 || raw[0] != licenseKeyVersion {
+		return nil, fmt.Errorf("%w: unsupported version", ErrInvalidLicenseKey)
+	}
+
+	if int(raw[1]) >= len(licenseKeyAlgorithms) || licenseKeyAlgorithms[raw[1]] == "" {
+		return nil, fmt.Errorf("%w: unknown algorithm %d", ErrInvalidLicenseKey, raw[1])
+	}
+
+	algorithm := licenseKeyAlgorithms[raw[1]]
+	keyIDEnd := 3 + int(raw[2]&^licenseKeyHexKeyID)
+
+	if len(raw) < keyIDEnd {
+		return nil, fmt.Errorf("%w: truncated key ID", ErrInvalidLicenseKey)
+	}
+
+	keyID := string(raw[3:keyIDEnd])
+	if raw[2]&licenseKeyHexKeyID != 0 {
+		keyID = hex.EncodeToString(raw[3:keyIDEnd])
+	}
+
+	size, n := binary.Uvarint(raw[keyIDEnd:])
+	if n <= 0 || size > uint64(len(raw)-keyIDEnd-n) {
+		return nil, fmt.Errorf("%w: truncated payload", ErrInvalidLicenseKey)
+	}
+
+	payload := raw[keyIDEnd+n : keyIDEnd+n+int(size)]
+	signature := raw[keyIDEnd+n+int(size):]
+
+	signedLicense := &SignedLicense{
+		Format:    FormatLicenseKey,
+		Payload:   base64.StdEncoding.EncodeToString(payload),
+		Signature: base64.StdEncoding.EncodeToString(signature),
+		KeyID:     keyID,
+		Algorithm: algorithm,
+	}
+
+	_, license, err := decodePayload(signedLicense)
+	if err != nil {
+		return nil, err
+	}
+
+	signedLicense.Data = *license
+	signedLicense.CreatedAt = license.IssuedAt
+
+	return signedLicense, nil
+}
+
+// ValidateLicenseKey parses and validates a license key in one call.
+func (v *Verifier) ValidateLicenseKey(key string) (*SignedLicense, *ValidationResult, error) {
+	signedLicense, err := v.ParseLicenseKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := v.ValidateLicense(signedLicense)
+
+	return signedLicense, result, nil
+}
+
+// decodeLicenseKeyPayload handles license-key-format licenses, where the
+// signature covers the CBOR claims payload itself.
+func decodeLicenseKeyPayload(signedLicense *SignedLicense) ([]byte, *License, error) {
+	payload, err := base64.StdEncoding.DecodeString(signedLicense.Payload)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: payload is not valid base64", ErrInvalidPayload)
+	}
+
+	license, err := decodeCOSEClaims(payload)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return payload, license, nil
+}
+
+// decodeLicenseKey normalizes and base32-decodes a license key.
+func decodeLicenseKey(key string) ([]byte, error) {
+	var normalized strings.Builder
+
+	for i, r := range key {
+		switch r = unicode.ToUpper(r); {
+		case r == '-' || unicode.IsSpace(r):
+			continue
+		case r == 'O':
+			r = '0'
+		case r == 'I' || r == 'L':
+			r = '1'
+		case !strings.ContainsRune(licenseKeyAlphabet, r):
+			return nil, fmt.Errorf("%w: invalid character %q at position %d", ErrInvalidLicenseKey, r, i+1)
+		}
+
+		normalized.WriteRune(r)
+	}
+
+	raw, err := licenseKeyEncoding.DecodeString(normalized.String())
+	if err != nil {
+		// A dropped or extra character leaves an impossible length.
+		return nil, ErrLicenseKeyChecksum
+	}
+
+	return raw, nil
+}
@@ -0,0 +1,191 @@
+/*******************************************************************
+
+		::          ::        +--------+-----------------------+
+		  ::      ::          | Author | Dmitry Novikov        |
+		::::::::::::::        | Email  | dredfort.42@gmail.com |
+	  ::::  ::::::  ::::      +--------+-----------------------+
+	::::::::::::::::::::::
+	::  ::::::::::::::  ::    File     | licensekey_test.go
+	::  ::          ::  ::    Created  | 2026-10-16
+		  ::::  ::::          Modified | 2026-10-16
+
+	GitHub:   https://github.com/dredfort42
+	LinkedIn: https://linkedin.com/in/novikov-da
+
+*******************************************************************/
+
+package licenser_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	licenser "github.com/dredfort42/go_licenser"
+)
+
+func TestLicenseKey(t *testing.T) {
+	manager := newEd25519Manager(t, licenser.Config{})
+
+	license := licenser.License{
+		Customer: "Key Customer",
+		AppID:    "key-app",
+		Services: []licenser.Service{{ID: "test", Name: "Test"}},
+		Features: map[string]bool{"export": true},
+	}
+
+	key, err := manager.GenerateLicenseKey(&license)
+	if err != nil {
+		t.Fatalf("Failed to generate license key: %v", err)
+	}
+
+	// replaceAt swaps the character at index i of key for a different valid one.
+	replaceAt := func(key string, i int) string {
+		replacement := byte('A')
+		if key[i] == 'A' {
+			replacement = 'B'
+		}
+
+		return key[:i] + string(replacement) + key[i+1:]
+	}
+
+	t.Run("Format", func(t *testing.T) {
+		if strings.ContainsAny(key, " \n{}\"") {
+			t.Errorf("License key should be a single typeable string, got %s", key)
+		}
+
+		for _, group := range strings.Split(key, "-") {
+			if len(group) > 5 {
+				t.Errorf("Expected groups of at most 5 characters, got '%s'", group)
+			}
+		}
+
+		// The 64-byte signature dominates; the CBOR claims add under 80 bytes.
+		if len(key) > 310 {
+			t.Errorf("Expected an Ed25519 license key of at most 310 characters, got %d", len(key))
+		}
+	})
+
+	t.Run("ValidateLicenseKey", func(t *testing.T) {
+		signedLicense, result, err := manager.ValidateLicenseKey(key)
+		if err != nil {
+			t.Fatalf("Failed to parse license key: %v", err)
+		}
+
+		if !result.Valid {
+			t.Errorf("License key should be valid, errors: %v", result.Errors)
+		}
+
+		if signedLicense.Data.Customer != license.Customer || !signedLicense.Data.Features["export"] {
+			t.Errorf("Expected license data to be decoded, got %+v", signedLicense.Data)
+		}
+
+		if signedLicense.KeyID != manager.KeyID() || signedLicense.Format != licenser.FormatLicenseKey {
+			t.Errorf("Expected key ID %s in format %s, got %s in %s",
+				manager.KeyID(), licenser.FormatLicenseKey, signedLicense.KeyID, signedLicense.Format)
+		}
+
+		again, err := licenser.EncodeLicenseKey(signedLicense)
+		if err != nil {
+			t.Fatalf("Failed to encode license key: %v", err)
+		}
+
+		if again != key {
+			t.Error("Encoding a parsed license key should reproduce it")
+		}
+	})
+
+	t.Run("Normalization", func(t *testing.T) {
+		sloppy := " " + strings.ToLower(strings.ReplaceAll(key, "-", "")) + "\n"
+		sloppy = strings.NewReplacer("0", "o", "1", "l").Replace(sloppy)
+
+		if _, result, err := manager.ValidateLicenseKey(sloppy); err != nil || !result.Valid {
+			t.Errorf("Normalized license key should be valid, got %v", err)
+		}
+	})
+
+	t.Run("Typo", func(t *testing.T) {
+		typos := map[string]string{
+			"Substitution": replaceAt(key, len(key)/2),
+			"Dropped":      key[:10] + key[11:],
+		}
+
+		for name, typo := range typos {
+			t.Run(name, func(t *testing.T) {
+				if _, err := manager.ParseLicenseKey(typo); !errors.Is(err, licenser.ErrLicenseKeyChecksum) {
+					t.Errorf("Expected ErrLicenseKeyChecksum, got %v", err)
+				}
+			})
+		}
+	})
+
+	t.Run("InvalidCharacter", func(t *testing.T) {
+		_, err := manager.ParseLicenseKey("U" + key[1:])
+		if !errors.Is(err, licenser.ErrInvalidLicenseKey) {
+			t.Errorf("Expected ErrInvalidLicenseKey, got %v", err)
+		}
+	})
+
+	t.Run("Forgery", func(t *testing.T) {
+		other := newEd25519Manager(t, licenser.Config{KeyID: manager.KeyID()})
+
+		forged, err := other.GenerateLicenseKey(&license)
+		if err != nil {
+			t.Fatalf("Failed to generate license key: %v", err)
+		}
+
+		_, result, err := manager.ValidateLicenseKey(forged)
+		if err != nil {
+			t.Fatalf("Forged key with a valid checksum should parse, got %v", err)
+		}
+
+		if result.Valid || !contains(result.Errors[0], licenser.ErrSignatureVerification.Error()) {
+			t.Errorf("Expected signature verification failure, got %v", result.Errors)
+		}
+	})
+
+	t.Run("CustomKeyID", func(t *testing.T) {
+		custom := newEd25519Manager(t, licenser.Config{KeyID: "Key-2026"})
+
+		customKey, err := custom.GenerateLicenseKey(&license)
+		if err != nil {
+			t.Fatalf("Failed to generate license key: %v", err)
+		}
+
+		signedLicense, result, err := custom.ValidateLicenseKey(customKey)
+		if err != nil {
+			t.Fatalf("Failed to parse license key: %v", err)
+		}
+
+		if !result.Valid || signedLicense.KeyID != "Key-2026" {
+			t.Errorf("Expected a valid license with key ID Key-2026, got %s (errors: %v)", signedLicense.KeyID, result.Errors)
+		}
+	})
+
+	t.Run("JSONLicense", func(t *testing.T) {
+		signedLicense, err := manager.GenerateLicense(&license)
+		if err != nil {
+			t.Fatalf("Failed to generate license: %v", err)
+		}
+
+		if _, err := licenser.EncodeLicenseKey(signedLicense); !errors.Is(err, licenser.ErrUnsupportedFormat) {
+			t.Errorf("Expected ErrUnsupportedFormat, got %v", err)
+		}
+	})
+
+	t.Run("TokenLicense", func(t *testing.T) {
+		token, err := manager.GenerateToken(&license)
+		if err != nil {
+			t.Fatalf("Failed to generate token: %v", err)
+		}
+
+		signedLicense, err := manager.ParseToken(token)
+		if err != nil {
+			t.Fatalf("Failed to parse token: %v", err)
+		}
+
+		if _, err := licenser.EncodeLicenseKey(signedLicense); !errors.Is(err, licenser.ErrUnsupportedFormat) {
+			t.Errorf("Expected ErrUnsupportedFormat, got %v", err)
+		}
+	})
+}
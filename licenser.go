@@ -7,7 +7,7 @@
 	::::::::::::::::::::::
 	::  ::::::::::::::  ::    File     | licenser.go
 	::  ::          ::  ::    Created  | 2025-08-08
-		  ::::  ::::          Modified | 2025-08-08
+		  ::::  ::::          Modified | 2026-10-16
 
 	GitHub:   https://github.com/dredfort42
 	LinkedIn: https://linkedin.com/in/novikov-da
@@ -17,15 +17,16 @@
 package licenser
 
 import (
-	"crypto/rand"
+	"bytes"
+	"crypto"
 	"crypto/rsa"
-	"crypto/sha256"
 	"crypto/x509"
-	"encoding/base64"
 	"encoding/json"
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"strings"
 	"time"
@@ -37,23 +38,80 @@ var (
 	ErrInvalidPublicKey      = errors.New("invalid public key")
 	ErrNoPublicKey           = errors.New("no public key provided")
 	ErrLicenseExpired        = errors.New("license has expired")
+	ErrLicenseNotYetValid    = errors.New("license is not yet valid")
 	ErrInvalidSignature      = errors.New("invalid signature")
 	ErrSignatureVerification = errors.New("signature verification failed")
 	ErrGeneratorModeRequired = errors.New("generator mode is required")
 	ErrCustomerRequired      = errors.New("customer name is required")
 	ErrAppIDRequired         = errors.New("application ID is required")
 	ErrNoServicesAllowed     = errors.New("at least one service must be allowed")
+	ErrInvalidValidityPeriod = errors.New("license must become valid before it expires")
+	ErrUnsupportedAlgorithm  = errors.New("unsupported signing algorithm")
+	ErrAlgorithmNotAllowed   = errors.New("signing algorithm is not allowed")
+	ErrAlgorithmMismatch     = errors.New("signing algorithm does not match the verification key")
+	ErrUnknownKeyID          = errors.New("unknown signing key ID")
+	ErrDuplicateKeyID        = errors.New("duplicate key ID")
+	ErrKeyRetired            = errors.New("signing key is retired")
+	ErrPassphraseRequired    = errors.New("private key passphrase is required")
+	ErrIncorrectPassphrase   = errors.New("incorrect private key passphrase")
+	ErrInvalidPayload        = errors.New("invalid signed payload")
+	ErrPayloadMismatch       = errors.New("license data does not match the signed payload")
+	ErrUnsupportedFormat     = errors.New("unsupported license format")
+	ErrInvalidToken          = errors.New("invalid license token")
+	ErrInvalidLicenseKey     = errors.New("invalid license key")
+	ErrLicenseKeyChecksum    = errors.New("license key checksum mismatch, check the key for typos")
+	ErrInvalidCOSE           = errors.New("invalid COSE_Sign1 message")
+	ErrPrivateKeyExists      = errors.New("private key file already exists")
+	ErrNoPrivateKey          = errors.New("no private key available")
+	ErrInvalidJWK            = errors.New("invalid JSON Web Key")
 )
 
+// errValidatorMode is returned by private key operations of a manager that
+// is not in generator mode.
+var errValidatorMode = fmt.Errorf("%w: %w", ErrNoPrivateKey, ErrGeneratorModeRequired)
+
 // Constants.
 const (
 	DefaultKeySize      = 2048
+	MinRSAKeySize       = 2048
 	StatusActive        = "active"
 	StatusExpired       = "expired"
+	StatusNotYetValid   = "not_yet_valid"
+	StatusGrace         = "grace"
 	LicenseExpired      = "License expired"
 	LicenseNeverExpired = "License never expired"
+	TimestampLayout     = "2006-01-02 15:04:05 MST"
+	AlgorithmRS256      = "RS256"
+	AlgorithmPS256      = "PS256"
+	AlgorithmEdDSA      = "EdDSA"
+	AlgorithmES256      = "ES256"
+	AlgorithmES384      = "ES384"
+	DefaultAlgorithm    = AlgorithmRS256
+	FormatJSON          = "json"
+	FormatJWS           = "jws"
+	FormatCOSE          = "cose"
+	FormatLicenseKey    = "key"
+	FileFormatJSON      = "json"
+	FileFormatPEM       = "pem"
+	FileFormatCOSE      = "cose"
+)
+
+// Default permissions of files written by the manager.
+const (
+	DefaultLicenseFileMode    os.FileMode = 0600
+	DefaultPrivateKeyFileMode os.FileMode = 0600
+	DefaultPublicKeyFileMode  os.FileMode = 0644
 )
 
+// supportedAlgorithms lists every signing algorithm this package implements.
+var supportedAlgorithms = []string{
+	AlgorithmRS256,
+	AlgorithmPS256,
+	AlgorithmEdDSA,
+	AlgorithmES256,
+	AlgorithmES384,
+}
+
 // Service represents a licensed service.
 type Service struct {
 	ID          string            `json:"id"`                    // Unique identifier for the service
@@ -70,15 +128,24 @@ type License struct {
 	Limits      map[string]int    `json:"limits,omitempty"`      // Usage limits
 	Features    map[string]bool   `json:"features,omitempty"`    // Feature flags
 	IssuedAt    int64             `json:"issued_at"`             // License issuance timestamp
+	NotBefore   int64             `json:"not_before,omitempty"`  // License activation timestamp
 	ExpiresAt   int64             `json:"expires_at,omitempty"`  // License expiration timestamp
 	Metadata    map[string]string `json:"metadata,omitempty"`    // Optional metadata associated with the license
 	Version     string            `json:"version,omitempty"`     // License version
 	Environment string            `json:"environment,omitempty"` // License environment
+
+	// Extensions holds top-level claims this version of the package does not
+	// know about, such as fields added by a newer issuer. They are preserved
+	// when the license is marshalled again.
+	Extensions map[string]json.RawMessage `json:"-"`
 }
 
 // SignedLicense represents a complete signed license.
 type SignedLicense struct {
 	Data      License `json:"data"`                // License data
+	Format    string  `json:"format,omitempty"`    // Signature envelope (default: json)
+	Protected string  `json:"protected,omitempty"` // Protected header of enveloped formats (base64url JWS header)
+	Payload   string  `json:"payload,omitempty"`   // Base64 of the exact signed bytes (empty in legacy licenses)
 	Signature string  `json:"signature"`           // License signature
 	KeyID     string  `json:"key_id,omitempty"`    // Key ID used for signing
 	Algorithm string  `json:"algorithm,omitempty"` // Signing algorithm
@@ -87,18 +154,22 @@ type SignedLicense struct {
 
 // LicenseInfo contains formatted license information for display.
 type LicenseInfo struct {
-	Customer        string            `json:"customer"`              // Customer name
-	AppID           string            `json:"app_id"`                // Application ID
-	IssuedAt        time.Time         `json:"issued_at"`             // Issuance timestamp
-	ExpiresAt       *time.Time        `json:"expires_at,omitempty"`  // Expiration timestamp
-	Status          string            `json:"status"`                // License status
-	TimeUntilExpiry string            `json:"time_until_expiry"`     // Time until expiration
-	Services        []Service         `json:"services"`              // Licensed services
-	Limits          map[string]int    `json:"limits,omitempty"`      // Usage limits
-	Features        map[string]bool   `json:"features,omitempty"`    // Feature flags
-	Metadata        map[string]string `json:"metadata,omitempty"`    // Optional metadata
-	Version         string            `json:"version,omitempty"`     // License version
-	Environment     string            `json:"environment,omitempty"` // License environment
+	Customer        string            `json:"customer"`                // Customer name
+	AppID           string            `json:"app_id"`                  // Application ID
+	IssuedAt        time.Time         `json:"issued_at"`               // Issuance timestamp
+	NotBefore       *time.Time        `json:"not_before,omitempty"`    // Activation timestamp
+	ExpiresAt       *time.Time        `json:"expires_at,omitempty"`    // Expiration timestamp
+	GraceEndsAt     *time.Time        `json:"grace_ends_at,omitempty"` // End of the grace period, set while in it
+	Status          string            `json:"status"`                  // License status
+	TimeUntilExpiry string            `json:"time_until_expiry"`       // Time until expiration
+	Services        []Service         `json:"services"`                // Licensed services
+	Limits          map[string]int    `json:"limits,omitempty"`        // Usage limits
+	Features        map[string]bool   `json:"features,omitempty"`      // Feature flags
+	Metadata        map[string]string `json:"metadata,omitempty"`      // Optional metadata
+	Version         string            `json:"version,omitempty"`       // License version
+	Environment     string            `json:"environment,omitempty"`   // License environment
+
+	Extensions map[string]json.RawMessage `json:"extensions,omitempty"` // Claims unknown to this version
 }
 
 // Config holds configuration for the license manager.
@@ -109,6 +180,49 @@ type Config struct {
 	PublicKeyPEM   string `json:"public_key_pem,omitempty"`   // PEM-encoded public key
 	KeySize        int    `json:"key_size,omitempty"`         // Size of the key in bits
 	GeneratorMode  bool   `json:"generator_mode,omitempty"`   // Whether to operate in generator mode
+	// Signing algorithm (default: derived from the key, RS256)
+	Algorithm string `json:"algorithm,omitempty"`
+	// Algorithms accepted during validation (default: all supported)
+	AllowedAlgorithms []string `json:"allowed_algorithms,omitempty"`
+	// ID stamped on issued licenses (default: key fingerprint)
+	KeyID        string `json:"key_id,omitempty"`
+	KeyRetiresAt int64  `json:"key_retires_at,omitempty"` // Retirement timestamp of the manager's own key
+	PKCS8        bool   `json:"pkcs8,omitempty"`          // Export private keys as PKCS#8
+	// Encoding written by SaveLicense: json (default), pem or cose
+	FileFormat string `json:"file_format,omitempty"`
+
+	// Tolerated clock drift around NotBefore and ExpiresAt
+	ClockSkew time.Duration `json:"clock_skew,omitempty"`
+	// Time after expiry during which licenses stay valid with a warning
+	GracePeriod time.Duration `json:"grace_period,omitempty"`
+
+	// Warn about licenses expiring within this window (default: no warning)
+	ExpiryWarningWindow time.Duration `json:"expiry_warning_window,omitempty"`
+	// Algorithms accepted with a warning
+	DeprecatedAlgorithms []string `json:"deprecated_algorithms,omitempty"`
+
+	// Additional keys accepted during validation
+	TrustedKeys []TrustedKey `json:"trusted_keys,omitempty"`
+	// JWKS document with additional trusted keys
+	TrustedKeysJWKSPath string `json:"trusted_keys_jwks_path,omitempty"`
+
+	// Permissions of saved licenses (default: 0600)
+	LicenseFileMode os.FileMode `json:"license_file_mode,omitempty"`
+	// Permissions of saved private keys (default: 0600)
+	PrivateKeyFileMode os.FileMode `json:"private_key_file_mode,omitempty"`
+	// Permissions of saved public keys (default: 0644)
+	PublicKeyFileMode os.FileMode `json:"public_key_file_mode,omitempty"`
+	// Allow SaveKeys to replace an existing private key file
+	OverwritePrivateKey bool `json:"overwrite_private_key,omitempty"`
+
+	FS    fs.FS `json:"-"` // File system for the key paths above (default: the operating system)
+	Clock Clock `json:"-"` // Source of the current time (default: SystemClock)
+
+	Passphrase     string                 `json:"-"` // Passphrase protecting the private key at rest
+	PassphraseFunc func() (string, error) `json:"-"` // Passphrase callback, used when Passphrase is empty
+
+	Signer   Signer            `json:"-"` // Custom signer, overrides the private key settings
+	Verifier SignatureVerifier `json:"-"` // Custom verifier, overrides the public key settings
 }
 
 // ValidationResult contains the result of license validation.
@@ -116,309 +230,240 @@ type ValidationResult struct {
 	Valid    bool     `json:"valid"`              // Indicates if the license is valid
 	Errors   []string `json:"errors,omitempty"`   // List of validation errors
 	Warnings []string `json:"warnings,omitempty"` // List of validation warnings
+
+	// Issues holds the errors and warnings above as structured values with
+	// stable codes, in the order they were found.
+	Issues []ValidationIssue `json:"issues,omitempty"`
 }
 
 // Builder provides a fluent interface for building licenses.
 type Builder struct {
 	license License
+	clock   Clock
 }
 
-// Manager handles license generation and validation.
+// Manager handles license generation and validation. It combines an Issuer,
+// present in generator mode, with a Verifier for the same keys.
 type Manager struct {
-	privateKey *rsa.PrivateKey
-	publicKey  *rsa.PublicKey
-	config     Config
+	issuer   *Issuer // Nil unless in generator mode
+	verifier *Verifier
+	config   Config
 }
 
 // NewManager creates a new license manager.
 func NewManager(config Config) (*Manager, error) {
-	m := &Manager{config: config}
+	m := &Manager{config: config.withDefaults()}
 
-	if config.KeySize == 0 {
-		m.config.KeySize = DefaultKeySize
-	}
-
-	var err error
+	var publicKey crypto.PublicKey
 
 	if config.GeneratorMode {
-		// Load or generate private key
-		switch {
-		case config.PrivateKeyPEM != "":
-			m.privateKey, err = parsePrivateKeyFromPEM(config.PrivateKeyPEM)
-		case config.PrivateKeyPath != "":
-			m.privateKey, err = loadPrivateKeyFromFile(config.PrivateKeyPath)
-		default:
-			m.privateKey, err = rsa.GenerateKey(rand.Reader, m.config.KeySize)
-		}
-
+		issuer, err := NewIssuer(m.config)
 		if err != nil {
-			return nil, fmt.Errorf("failed to setup private key: %w", err)
+			return nil, err
 		}
 
-		m.publicKey = &m.privateKey.PublicKey
+		m.issuer = issuer
+		m.config = issuer.config
+		publicKey = issuer.PublicKey()
 	}
 
-	// Load public key if specified separately
-	if config.PublicKeyPEM != "" {
-		m.publicKey, err = parsePublicKeyFromPEM(config.PublicKeyPEM)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse public key: %w", err)
-		}
-	} else if config.PublicKeyPath != "" {
-		m.publicKey, err = loadPublicKeyFromFile(config.PublicKeyPath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load public key: %w", err)
-		}
+	verifier, err := newVerifier(m.config, publicKey)
+	if err != nil {
+		return nil, err
 	}
 
-	if m.publicKey == nil {
-		return nil, ErrNoPublicKey
-	}
+	m.verifier = verifier
+	m.config = verifier.config
 
 	return m, nil
 }
 
-// GenerateLicense creates a signed license.
-func (m *Manager) GenerateLicense(license *License) (*SignedLicense, error) {
-	if !m.config.GeneratorMode {
-		return nil, ErrGeneratorModeRequired
-	}
-
-	if license.Customer == "" {
-		return nil, ErrCustomerRequired
-	}
-
-	if license.AppID == "" {
-		return nil, ErrAppIDRequired
+// withDefaults returns a copy of the config with defaults filled in.
+func (c Config) withDefaults() Config {
+	if c.KeySize == 0 {
+		c.KeySize = DefaultKeySize
 	}
 
-	if len(license.Services) == 0 {
-		return nil, ErrNoServicesAllowed
+	if c.LicenseFileMode == 0 {
+		c.LicenseFileMode = DefaultLicenseFileMode
 	}
 
-	if license.IssuedAt == 0 {
-		license.IssuedAt = time.Now().Unix()
+	if c.PrivateKeyFileMode == 0 {
+		c.PrivateKeyFileMode = DefaultPrivateKeyFileMode
 	}
 
-	data, err := json.Marshal(license)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal license: %w", err)
+	if c.PublicKeyFileMode == 0 {
+		c.PublicKeyFileMode = DefaultPublicKeyFileMode
 	}
 
-	signature, err := m.signData(data)
-	if err != nil {
-		return nil, fmt.Errorf("failed to sign license: %w", err)
+	if c.Clock == nil {
+		c.Clock = SystemClock
 	}
 
-	return &SignedLicense{
-		Data:      *license,
-		Signature: signature,
-		CreatedAt: time.Now().Unix(),
-		Algorithm: "RS256",
-	}, nil
+	return c
 }
 
-// ValidateLicense validates a signed license.
-func (m *Manager) ValidateLicense(signedLicense *SignedLicense) *ValidationResult {
-	result := &ValidationResult{Valid: true}
-
-	// Verify signature
-	data, err := json.Marshal(signedLicense.Data)
-	if err != nil {
-		result.Valid = false
-		result.Errors = append(result.Errors, "failed to marshal license data")
-
-		return result
-	}
-
-	if err := m.verifySignature(data, signedLicense.Signature); err != nil {
-		result.Valid = false
-		result.Errors = append(result.Errors, "signature verification failed")
-	}
+// Issuer returns the issuing half of the manager, or nil outside generator mode.
+func (m *Manager) Issuer() *Issuer {
+	return m.issuer
+}
 
-	// Check expiration
-	if signedLicense.Data.ExpiresAt > 0 && time.Now().Unix() > signedLicense.Data.ExpiresAt {
-		result.Valid = false
-		result.Errors = append(result.Errors, "license has expired")
-	}
+// Verifier returns the validating half of the manager.
+func (m *Manager) Verifier() *Verifier {
+	return m.verifier
+}
 
-	// Basic validation
-	if signedLicense.Data.Customer == "" {
-		result.Valid = false
-		result.Errors = append(result.Errors, "customer is required")
+// GenerateLicense creates a signed license. With Config.FileFormat set to
+// FileFormatCOSE the license is signed as a COSE_Sign1 message.
+func (m *Manager) GenerateLicense(license *License) (*SignedLicense, error) {
+	if m.issuer == nil {
+		return nil, ErrGeneratorModeRequired
 	}
 
-	if signedLicense.Data.AppID == "" {
-		result.Valid = false
-		result.Errors = append(result.Errors, "app ID is required")
-	}
+	return m.issuer.GenerateLicense(license)
+}
 
-	if len(signedLicense.Data.Services) == 0 {
-		result.Valid = false
-		result.Errors = append(result.Errors, "at least one service is required")
-	}
+// ValidateLicense validates a signed license.
+func (m *Manager) ValidateLicense(signedLicense *SignedLicense) *ValidationResult {
+	return m.verifier.ValidateLicense(signedLicense)
+}
 
-	return result
+// ValidateLicenseAt validates a signed license as of the given time.
+func (m *Manager) ValidateLicenseAt(signedLicense *SignedLicense, at time.Time) *ValidationResult {
+	return m.verifier.ValidateLicenseAt(signedLicense, at)
 }
 
-// SaveLicense saves a license to file.
+// SaveLicense saves a license to file in the encoding selected by
+// Config.FileFormat. The file is replaced atomically, so a crash never leaves
+// a truncated license behind.
 func (m *Manager) SaveLicense(signedLicense *SignedLicense, filePath string) error {
-	data, err := json.MarshalIndent(signedLicense, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal license: %w", err)
-	}
-
-	return os.WriteFile(filePath, data, 0600)
+	return writeFile(filePath, m.config.LicenseFileMode, true, func(w io.Writer) error {
+		return m.WriteLicense(w, signedLicense)
+	})
 }
 
-// LoadLicense loads a license from file.
+// LoadLicense loads a license from file. JSON, PEM-armored and COSE licenses
+// are detected automatically.
 func (m *Manager) LoadLicense(filePath string) (*SignedLicense, error) {
-	// #nosec G304
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read license file: %w", err)
-	}
-
-	var signedLicense SignedLicense
-	if err := json.Unmarshal(data, &signedLicense); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal license: %w", err)
-	}
-
-	return &signedLicense, nil
+	return m.verifier.LoadLicense(filePath)
 }
 
 // LoadAndValidateLicense loads and validates a license in one call.
 func (m *Manager) LoadAndValidateLicense(filePath string) (*SignedLicense, *ValidationResult, error) {
-	signedLicense, err := m.LoadLicense(filePath)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	result := m.ValidateLicense(signedLicense)
-
-	return signedLicense, result, nil
+	return m.verifier.LoadAndValidateLicense(filePath)
 }
 
-// SaveKeys saves private and public keys to files.
+// SaveKeys saves private and public keys to files. The private key is
+// encrypted when a passphrase is configured, and an existing private key file
+// is only replaced when Config.OverwritePrivateKey is set.
 func (m *Manager) SaveKeys(privateKeyPath, publicKeyPath string) error {
-	// Save private key
-	privateKeyPEM := m.ExportPrivateKey()
-	if err := os.WriteFile(privateKeyPath, []byte(privateKeyPEM), 0600); err != nil {
-		return fmt.Errorf("failed to save private key: %w", err)
-	}
-
-	// Save public key
-	publicKeyPEM := m.ExportPublicKey()
-	if err := os.WriteFile(publicKeyPath, []byte(publicKeyPEM), 0600); err != nil {
-		return fmt.Errorf("failed to save public key: %w", err)
+	if m.issuer == nil {
+		return fmt.Errorf("failed to save private key: %w", errValidatorMode)
 	}
 
-	return nil
+	return m.issuer.SaveKeys(privateKeyPath, publicKeyPath)
 }
 
 // SavePublicKey saves the public key to a file.
 func (m *Manager) SavePublicKey(filePath string) error {
-	if m.publicKey == nil {
-		return ErrNoPublicKey
-	}
-
-	publicKeyPEM := m.ExportPublicKey()
-
-	return os.WriteFile(filePath, []byte(publicKeyPEM), 0600)
+	return m.verifier.SavePublicKey(filePath)
 }
 
 // ExportKeys returns both private and public keys as PEM strings.
 func (m *Manager) ExportKeys() (privateKey string, publicKey string, err error) {
-	return m.ExportPrivateKey(), m.ExportPublicKey(), nil
+	if m.issuer == nil {
+		return "", "", errValidatorMode
+	}
+
+	if privateKey, err = m.issuer.ExportPrivateKey(); err != nil {
+		return "", "", err
+	}
+
+	if publicKey, err = m.ExportPublicKey(); err != nil {
+		return "", "", err
+	}
+
+	return privateKey, publicKey, nil
 }
 
 // ExportPrivateKey exports the private key as PEM.
-func (m *Manager) ExportPrivateKey() string {
-	privateKeyBytes := x509.MarshalPKCS1PrivateKey(m.privateKey)
-	privateKeyPEM := pem.EncodeToMemory(&pem.Block{
-		Type:  "RSA PRIVATE KEY",
-		Bytes: privateKeyBytes,
-	})
+// RSA keys are encoded as PKCS#1, ECDSA keys as SEC1 and Ed25519 keys as
+// PKCS#8; with Config.PKCS8 set every key type is encoded as PKCS#8.
+// When a passphrase is configured the key is exported as encrypted PKCS#8.
+// Outside generator mode ErrNoPrivateKey is returned.
+func (m *Manager) ExportPrivateKey() (string, error) {
+	if m.issuer == nil {
+		return "", errValidatorMode
+	}
 
-	return string(privateKeyPEM)
+	return m.issuer.ExportPrivateKey()
 }
 
-// ExportPublicKey exports the public key as PEM.
-func (m *Manager) ExportPublicKey() string {
-	publicKeyBytes, err := x509.MarshalPKIXPublicKey(m.publicKey)
-	if err != nil {
-		return ""
+// ExportEncryptedPrivateKey exports the private key as PKCS#8 encrypted with
+// passphrase (PBES2, PBKDF2-HMAC-SHA256 and AES-256-CBC).
+func (m *Manager) ExportEncryptedPrivateKey(passphrase string) (string, error) {
+	if m.issuer == nil {
+		return "", errValidatorMode
 	}
 
-	publicKeyPEM := pem.EncodeToMemory(&pem.Block{
-		Type:  "PUBLIC KEY",
-		Bytes: publicKeyBytes,
-	})
+	return m.issuer.ExportEncryptedPrivateKey(passphrase)
+}
 
-	return string(publicKeyPEM)
+// ExportPublicKey exports the public key as PEM.
+func (m *Manager) ExportPublicKey() (string, error) {
+	return m.verifier.ExportPublicKey()
 }
 
-// GetPublicKey returns the RSA public key.
+// GetPublicKey returns the RSA public key, or nil if the manager uses another key type.
 func (m *Manager) GetPublicKey() *rsa.PublicKey {
-	return m.publicKey
+	return m.verifier.GetPublicKey()
+}
+
+// PublicKey returns the public key used for validation.
+func (m *Manager) PublicKey() crypto.PublicKey {
+	return m.verifier.PublicKey()
 }
 
 // IsExpired checks if a license is expired.
 func (m *Manager) IsExpired(license *License) bool {
-	return license.ExpiresAt > 0 && time.Now().Unix() > license.ExpiresAt
+	return m.verifier.IsExpired(license)
+}
+
+// IsNotYetValid checks if a license has not reached its activation time.
+func (m *Manager) IsNotYetValid(license *License) bool {
+	return m.verifier.IsNotYetValid(license)
+}
+
+// InGracePeriod checks if a license has expired but is still accepted.
+func (m *Manager) InGracePeriod(license *License) bool {
+	return m.verifier.InGracePeriod(license)
 }
 
 // IsActive checks if a license is currently active.
 func (m *Manager) IsActive(license *License) bool {
-	return !m.IsExpired(license)
+	return m.verifier.IsActive(license)
 }
 
 // CheckExpiration returns an error if the license is expired.
 func (m *Manager) CheckExpiration(license *License) error {
-	if m.IsExpired(license) {
-		return ErrLicenseExpired
-	}
+	return m.verifier.CheckExpiration(license)
+}
 
-	return nil
+// CheckValidityPeriod returns an error if the license is expired or not yet valid.
+func (m *Manager) CheckValidityPeriod(license *License) error {
+	return m.verifier.CheckValidityPeriod(license)
 }
 
 // GetLicenseInfo creates formatted license information.
 func (m *Manager) GetLicenseInfo(license *License) *LicenseInfo {
-	info := &LicenseInfo{
-		Customer:    license.Customer,
-		AppID:       license.AppID,
-		IssuedAt:    time.Unix(license.IssuedAt, 0),
-		Services:    license.Services,
-		Limits:      license.Limits,
-		Features:    license.Features,
-		Metadata:    license.Metadata,
-		Version:     license.Version,
-		Environment: license.Environment,
-	}
-
-	if license.ExpiresAt > 0 {
-		expiresAt := time.Unix(license.ExpiresAt, 0)
-		info.ExpiresAt = &expiresAt
-
-		if m.IsExpired(license) {
-			info.Status = StatusExpired
-			info.TimeUntilExpiry = LicenseExpired
-		} else {
-			info.Status = StatusActive
-			remaining := time.Until(expiresAt)
-			info.TimeUntilExpiry = formatDuration(remaining)
-		}
-	} else {
-		info.Status = StatusActive
-		info.TimeUntilExpiry = LicenseNeverExpired
-	}
-
-	return info
+	return m.verifier.GetLicenseInfo(license)
 }
 
-// NewBuilder creates a new license builder.
-func NewBuilder() *Builder {
+// NewBuilder creates a new license builder. WithClock sets the clock used
+// for IssuedAt and WithExpirationDuration.
+func NewBuilder(opts ...Option) *Builder {
 	return &Builder{
+		clock: newOptions(opts).clock,
 		license: License{
 			Services: make([]Service, 0),
 			Limits:   make(map[string]int),
@@ -470,6 +515,20 @@ func (b *Builder) WithFeature(key string, enabled bool) *Builder {
 	return b
 }
 
+// WithNotBefore sets the activation timestamp.
+func (b *Builder) WithNotBefore(notBefore int64) *Builder {
+	b.license.NotBefore = notBefore
+
+	return b
+}
+
+// WithNotBeforeTime sets the activation time.
+func (b *Builder) WithNotBeforeTime(notBefore time.Time) *Builder {
+	b.license.NotBefore = notBefore.Unix()
+
+	return b
+}
+
 // WithExpiration sets the expiration timestamp.
 func (b *Builder) WithExpiration(expiresAt int64) *Builder {
 	b.license.ExpiresAt = expiresAt
@@ -486,7 +545,7 @@ func (b *Builder) WithExpirationTime(expiresAt time.Time) *Builder {
 
 // WithExpirationDuration sets expiration relative to now.
 func (b *Builder) WithExpirationDuration(duration time.Duration) *Builder {
-	b.license.ExpiresAt = time.Now().Add(duration).Unix()
+	b.license.ExpiresAt = b.clock.Now().Add(duration).Unix()
 
 	return b
 }
@@ -515,7 +574,7 @@ func (b *Builder) WithEnvironment(environment string) *Builder {
 // Build returns the built license.
 func (b *Builder) Build() License {
 	if b.license.IssuedAt == 0 {
-		b.license.IssuedAt = time.Now().Unix()
+		b.license.IssuedAt = b.clock.Now().Unix()
 	}
 
 	return b.license
@@ -535,79 +594,76 @@ func (b *Builder) Validate() error {
 		return ErrNoServicesAllowed
 	}
 
+	if b.license.NotBefore > 0 && b.license.ExpiresAt > 0 && b.license.NotBefore >= b.license.ExpiresAt {
+		return ErrInvalidValidityPeriod
+	}
+
 	return nil
 }
 
 // Helper functions
 
-func (m *Manager) signData(data []byte) (string, error) {
-	hash := sha256.Sum256(data)
+// encodeLicense encodes a license in the given file format.
+func encodeLicense(signedLicense *SignedLicense, fileFormat string) ([]byte, error) {
+	switch fileFormat {
+	case "", FileFormatJSON:
+		data, err := json.MarshalIndent(signedLicense, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal license: %w", err)
+		}
 
-	signature, err := rsa.SignPKCS1v15(rand.Reader, m.privateKey, 0, hash[:])
-	if err != nil {
-		return "", err
+		return data, nil
+	case FileFormatPEM:
+		return EncodeLicensePEM(signedLicense)
+	case FileFormatCOSE:
+		return EncodeCOSE(signedLicense)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, fileFormat)
 	}
-
-	return base64.StdEncoding.EncodeToString(signature), nil
 }
 
-func (m *Manager) verifySignature(data []byte, signatureStr string) error {
-	signature, err := base64.StdEncoding.DecodeString(signatureStr)
-	if err != nil {
-		return ErrInvalidSignature
+// encodePublicKeyPEM encodes a public key as PKIX PEM.
+func encodePublicKeyPEM(publicKey crypto.PublicKey) (string, error) {
+	if publicKey == nil {
+		return "", ErrNoPublicKey
 	}
 
-	hash := sha256.Sum256(data)
-
-	return rsa.VerifyPKCS1v15(m.publicKey, 0, hash[:], signature)
-}
-
-func loadPrivateKeyFromFile(filePath string) (*rsa.PrivateKey, error) {
-	// #nosec G304
-	data, err := os.ReadFile(filePath)
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(publicKey)
 	if err != nil {
-		return nil, err
+		return "", fmt.Errorf("%w: %w", ErrInvalidPublicKey, err)
 	}
 
-	return parsePrivateKeyFromPEM(string(data))
-}
-
-func loadPublicKeyFromFile(filePath string) (*rsa.PublicKey, error) {
-	// #nosec G304
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		return nil, err
-	}
+	publicKeyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  pemTypePublicKey,
+		Bytes: publicKeyBytes,
+	})
 
-	return parsePublicKeyFromPEM(string(data))
+	return string(publicKeyPEM), nil
 }
 
-func parsePrivateKeyFromPEM(pemData string) (*rsa.PrivateKey, error) {
-	block, _ := pem.Decode([]byte(pemData))
-	if block == nil {
-		return nil, ErrInvalidPrivateKey
+// decodeLicense detects the encoding of a license file and decodes it.
+func decodeLicense(data []byte) (*SignedLicense, error) {
+	// A tagged or untagged COSE_Sign1 message starts with tag 18 or a 4-element array.
+	if len(data) > 0 && (data[0] == 0xd2 || data[0] == 0x84) {
+		return DecodeCOSE(data)
 	}
 
-	return x509.ParsePKCS1PrivateKey(block.Bytes)
-}
+	trimmed := bytes.TrimSpace(data)
 
-func parsePublicKeyFromPEM(pemData string) (*rsa.PublicKey, error) {
-	block, _ := pem.Decode([]byte(pemData))
-	if block == nil {
-		return nil, ErrInvalidPublicKey
-	}
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		var signedLicense SignedLicense
+		if err := json.Unmarshal(trimmed, &signedLicense); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal license: %w", err)
+		}
 
-	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
-	if err != nil {
-		return nil, err
+		return &signedLicense, nil
 	}
 
-	rsaPub, ok := pub.(*rsa.PublicKey)
-	if !ok {
-		return nil, ErrInvalidPublicKey
+	if bytes.Contains(trimmed, []byte("-----BEGIN ")) {
+		return DecodeLicensePEM(trimmed)
 	}
 
-	return rsaPub, nil
+	return nil, fmt.Errorf("%w: expected a JSON, PEM-armored or COSE license", ErrUnsupportedFormat)
 }
 
 func formatDuration(d time.Duration) string {
@@ -675,23 +731,23 @@ func HasServiceByName(license *License, serviceName string) bool {
 }
 
 // IsExpiringSoon checks if a license is expiring within the specified duration.
-func IsExpiringSoon(license *License, within time.Duration) bool {
+func IsExpiringSoon(license *License, within time.Duration, opts ...Option) bool {
 	if license.ExpiresAt == 0 {
 		return false
 	}
 
 	expiresAt := time.Unix(license.ExpiresAt, 0)
 
-	return time.Until(expiresAt) <= within
+	return expiresAt.Sub(newOptions(opts).clock.Now()) <= within
 }
 
 // CalculateRemainingTime calculates the remaining time for a license.
-func CalculateRemainingTime(expiresAt int64) time.Duration {
+func CalculateRemainingTime(expiresAt int64, opts ...Option) time.Duration {
 	if expiresAt == 0 {
 		return 0 // Never expires
 	}
 
-	remaining := time.Until(time.Unix(expiresAt, 0))
+	remaining := time.Unix(expiresAt, 0).Sub(newOptions(opts).clock.Now())
 	if remaining < 0 {
 		return 0 // Already expired
 	}
@@ -700,12 +756,12 @@ func CalculateRemainingTime(expiresAt int64) time.Duration {
 }
 
 // FormatTimeUntilExpiry formats the time remaining until expiration.
-func FormatTimeUntilExpiry(expiresAt int64) string {
+func FormatTimeUntilExpiry(expiresAt int64, opts ...Option) string {
 	if expiresAt == 0 {
 		return LicenseNeverExpired
 	}
 
-	remaining := CalculateRemainingTime(expiresAt)
+	remaining := CalculateRemainingTime(expiresAt, opts...)
 	if remaining == 0 {
 		return LicenseExpired
 	}
@@ -719,18 +775,14 @@ func FormatExpiry(expiresAt int64) string {
 		return LicenseNeverExpired
 	}
 
-	return time.Unix(expiresAt, 0).Format("2006-01-02 15:04:05 MST")
+	return time.Unix(expiresAt, 0).Format(TimestampLayout)
 }
 
-// GetLicenseStatus returns the status of a license.
-func GetLicenseStatus(license *License) string {
-	if license.ExpiresAt == 0 {
-		return StatusActive
-	}
-
-	if time.Now().Unix() > license.ExpiresAt {
-		return StatusExpired
-	}
+// GetLicenseStatus returns the status of a license. WithClockSkew and
+// WithGracePeriod apply the same leeway as Config.ClockSkew and
+// Config.GracePeriod.
+func GetLicenseStatus(license *License, opts ...Option) string {
+	o := newOptions(opts)
 
-	return StatusActive
+	return o.window.statusAt(license, o.clock.Now())
 }
@@ -7,7 +7,7 @@
 	::::::::::::::::::::::
 	::  ::::::::::::::  ::    File     | licenser_test.go
 	::  ::          ::  ::    Created  | 2025-08-08
-		  ::::  ::::          Modified | 2025-08-08
+		  ::::  ::::          Modified | 2026-10-16
 
 	GitHub:   https://github.com/dredfort42
 	LinkedIn: https://linkedin.com/in/novikov-da
@@ -17,6 +17,7 @@
 package licenser_test
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -411,6 +412,96 @@ func TestLicenseValidation(t *testing.T) {
 	})
 }
 
+func TestValidationWarnings(t *testing.T) {
+	now := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	newLicense := func() *licenser.License {
+		return &licenser.License{
+			Customer:  "Warning Customer",
+			AppID:     "warning-app",
+			Services:  []licenser.Service{{ID: "test", Name: "Test"}},
+			ExpiresAt: now.AddDate(1, 0, 0).Unix(),
+			Version:   "1.0.0",
+		}
+	}
+
+	warningsFor := func(t *testing.T, config licenser.Config, license *licenser.License) []string {
+		t.Helper()
+
+		config.GeneratorMode = true
+		config.Clock = licenser.FixedClock(now)
+
+		manager, err := licenser.NewManager(config)
+		if err != nil {
+			t.Fatalf("Failed to create manager: %v", err)
+		}
+
+		signedLicense, err := manager.GenerateLicense(license)
+		if err != nil {
+			t.Fatalf("Failed to generate license: %v", err)
+		}
+
+		result := manager.ValidateLicense(signedLicense)
+		if !result.Valid {
+			t.Fatalf("Warnings should not invalidate a license, errors: %v", result.Errors)
+		}
+
+		return result.Warnings
+	}
+
+	t.Run("None", func(t *testing.T) {
+		if warnings := warningsFor(t, licenser.Config{Algorithm: licenser.AlgorithmEdDSA}, newLicense()); len(warnings) != 0 {
+			t.Errorf("Expected no warnings, got %v", warnings)
+		}
+	})
+
+	t.Run("ExpiringSoon", func(t *testing.T) {
+		license := newLicense()
+		license.ExpiresAt = now.Add(10 * 24 * time.Hour).Unix()
+
+		config := licenser.Config{Algorithm: licenser.AlgorithmEdDSA, ExpiryWarningWindow: 30 * 24 * time.Hour}
+
+		warnings := warningsFor(t, config, license)
+		if len(warnings) != 1 || !contains(warnings[0], "(in 10d)") {
+			t.Errorf("Expected an expiry warning, got %v", warnings)
+		}
+
+		config.ExpiryWarningWindow = 7 * 24 * time.Hour
+		if warnings := warningsFor(t, config, license); len(warnings) != 0 {
+			t.Errorf("Expected no warnings outside the window, got %v", warnings)
+		}
+	})
+
+	t.Run("DeprecatedAlgorithm", func(t *testing.T) {
+		config := licenser.Config{
+			Algorithm:            licenser.AlgorithmES256,
+			DeprecatedAlgorithms: []string{licenser.AlgorithmES256},
+		}
+
+		warnings := warningsFor(t, config, newLicense())
+		if len(warnings) != 1 || !contains(warnings[0], "deprecated algorithm ES256") {
+			t.Errorf("Expected a deprecated algorithm warning, got %v", warnings)
+		}
+	})
+
+	t.Run("WeakKey", func(t *testing.T) {
+		warnings := warningsFor(t, licenser.Config{KeySize: 1024}, newLicense())
+		if len(warnings) != 1 || !contains(warnings[0], "1024-bit RSA key") {
+			t.Errorf("Expected a weak key warning, got %v", warnings)
+		}
+	})
+
+	t.Run("MissingVersion", func(t *testing.T) {
+		license := newLicense()
+		license.Version = ""
+
+		warnings := warningsFor(t, licenser.Config{Algorithm: licenser.AlgorithmEdDSA}, license)
+		if len(warnings) != 1 || warnings[0] != "license has no version" {
+			t.Errorf("Expected a missing version warning, got %v", warnings)
+		}
+	})
+}
+
 func TestFileOperations(t *testing.T) {
 	config := licenser.Config{
 		KeySize:       1024,
@@ -598,7 +689,7 @@ func TestKeyExport(t *testing.T) {
 	})
 
 	t.Run("ExportPrivateKey", func(t *testing.T) {
-		privateKey := manager.ExportPrivateKey()
+		privateKey := exportPrivateKey(t, manager)
 		if privateKey == "" {
 			t.Error("Private key should not be empty")
 		}
@@ -609,7 +700,7 @@ func TestKeyExport(t *testing.T) {
 	})
 
 	t.Run("ExportPublicKey", func(t *testing.T) {
-		publicKey := manager.ExportPublicKey()
+		publicKey := exportPublicKey(t, manager)
 		if publicKey == "" {
 			t.Error("Public key should not be empty")
 		}
@@ -625,6 +716,32 @@ func TestKeyExport(t *testing.T) {
 			t.Error("Public key should not be nil")
 		}
 	})
+
+	t.Run("ValidatorMode", func(t *testing.T) {
+		validator, err := licenser.NewManager(licenser.Config{PublicKeyPEM: exportPublicKey(t, manager)})
+		if err != nil {
+			t.Fatalf("Failed to create validator: %v", err)
+		}
+
+		if _, err := validator.ExportPrivateKey(); !errors.Is(err, licenser.ErrNoPrivateKey) {
+			t.Errorf("Expected ErrNoPrivateKey, got %v", err)
+		}
+
+		if _, _, err := validator.ExportKeys(); !errors.Is(err, licenser.ErrGeneratorModeRequired) {
+			t.Errorf("Expected ErrGeneratorModeRequired, got %v", err)
+		}
+
+		dir := t.TempDir()
+
+		err = validator.SaveKeys(filepath.Join(dir, "private.pem"), filepath.Join(dir, "public.pem"))
+		if !errors.Is(err, licenser.ErrNoPrivateKey) {
+			t.Errorf("Expected ErrNoPrivateKey, got %v", err)
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, "public.pem")); !os.IsNotExist(err) {
+			t.Error("No public key should be saved when the private key is missing")
+		}
+	})
 }
 
 func TestExpirationFunctions(t *testing.T) {
@@ -946,6 +1063,111 @@ func TestExpiration(t *testing.T) {
 	}
 }
 
+func TestNotBefore(t *testing.T) {
+	manager := newEd25519Manager(t, licenser.Config{})
+
+	newLicense := func(notBefore time.Time) *licenser.License {
+		license := licenser.NewBuilder().
+			WithCustomer("Future Customer").
+			WithAppID("future-app").
+			WithService(licenser.Service{ID: "test", Name: "Test"}).
+			WithNotBeforeTime(notBefore).
+			WithExpirationTime(notBefore.Add(90 * 24 * time.Hour)).
+			Build()
+
+		return &license
+	}
+
+	t.Run("NotYetValid", func(t *testing.T) {
+		license := newLicense(time.Now().Add(24 * time.Hour))
+
+		signedLicense, err := manager.GenerateLicense(license)
+		if err != nil {
+			t.Fatalf("Failed to generate license: %v", err)
+		}
+
+		result := manager.ValidateLicense(signedLicense)
+		if result.Valid || !contains(strings.Join(result.Errors, ";"), licenser.ErrLicenseNotYetValid.Error()) {
+			t.Errorf("Pre-issued license should not be valid yet, errors: %v", result.Errors)
+		}
+
+		if manager.IsExpired(license) || manager.IsActive(license) || !manager.IsNotYetValid(license) {
+			t.Error("Pre-issued license should be neither expired nor active")
+		}
+
+		if err := manager.CheckValidityPeriod(license); !errors.Is(err, licenser.ErrLicenseNotYetValid) {
+			t.Errorf("Expected ErrLicenseNotYetValid, got %v", err)
+		}
+
+		if status := licenser.GetLicenseStatus(license); status != licenser.StatusNotYetValid {
+			t.Errorf("Expected status '%s', got '%s'", licenser.StatusNotYetValid, status)
+		}
+
+		info := manager.GetLicenseInfo(license)
+		if info.Status != licenser.StatusNotYetValid || info.NotBefore == nil || info.NotBefore.Unix() != license.NotBefore {
+			t.Errorf("Unexpected license info: status '%s', not before %v", info.Status, info.NotBefore)
+		}
+	})
+
+	t.Run("Active", func(t *testing.T) {
+		license := newLicense(time.Now().Add(-time.Hour))
+
+		signedLicense, err := manager.GenerateLicense(license)
+		if err != nil {
+			t.Fatalf("Failed to generate license: %v", err)
+		}
+
+		if result := manager.ValidateLicense(signedLicense); !result.Valid {
+			t.Errorf("Activated license should be valid, errors: %v", result.Errors)
+		}
+
+		if status := licenser.GetLicenseStatus(license); status != licenser.StatusActive {
+			t.Errorf("Expected status '%s', got '%s'", licenser.StatusActive, status)
+		}
+
+		if err := manager.CheckValidityPeriod(license); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("Token", func(t *testing.T) {
+		license := newLicense(time.Now().Add(24 * time.Hour))
+
+		token, err := manager.GenerateToken(license)
+		if err != nil {
+			t.Fatalf("Failed to generate token: %v", err)
+		}
+
+		signedLicense, result, err := manager.ValidateToken(token)
+		if err != nil {
+			t.Fatalf("Failed to parse token: %v", err)
+		}
+
+		if signedLicense.Data.NotBefore != license.NotBefore || result.Valid {
+			t.Errorf("Token should carry nbf and not be valid yet, got %d %v", signedLicense.Data.NotBefore, result.Errors)
+		}
+	})
+
+	t.Run("InvalidPeriod", func(t *testing.T) {
+		license := newLicense(time.Now())
+		license.ExpiresAt = license.NotBefore
+
+		if _, err := manager.GenerateLicense(license); !errors.Is(err, licenser.ErrInvalidValidityPeriod) {
+			t.Errorf("Expected ErrInvalidValidityPeriod, got %v", err)
+		}
+
+		builder := licenser.NewBuilder().
+			WithCustomer("Customer").
+			WithAppID("app").
+			WithService(licenser.Service{ID: "test", Name: "Test"}).
+			WithNotBefore(200).
+			WithExpiration(100)
+		if err := builder.Validate(); !errors.Is(err, licenser.ErrInvalidValidityPeriod) {
+			t.Errorf("Expected ErrInvalidValidityPeriod, got %v", err)
+		}
+	})
+}
+
 func TestErrorCases(t *testing.T) {
 	t.Run("GenerateLicenseWithoutGeneratorMode", func(t *testing.T) {
 		// Create public key for validator mode
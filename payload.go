@@ -0,0 +1,187 @@
+/*******************************************************************
+
+		::          ::        +--------+-----------------------+
+		  ::      ::          | Author | Dmitry Novikov        |
+		::::::::::::::        | Email  | dredfort.42@gmail.com |
+	  ::::  ::::::  ::::      +--------+-----------------------+
+	::::::::::::::::::::::
+	::  ::::::::::::::  ::    File     | payload.go
+	::  ::          ::  ::    Created  | 2026-10-16
+		  ::::  ::::          Modified | 2026-10-16
+
+	GitHub:   https://github.com/dredfort42
+	LinkedIn: https://linkedin.com/in/novikov-da
+
+*******************************************************************/
+
+package licenser
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// The signed payload is the exact byte sequence the signature covers. It is
+// stored base64-encoded in SignedLicense.Payload so that verification never
+// depends on how the current License struct happens to marshal. The
+// SignedLicense.Data field is a decoded copy kept for convenience; it must
+// match the payload or validation fails.
+
+// encodePayload marshals license into the bytes that are signed.
+func encodePayload(license *License) ([]byte, error) {
+	return json.Marshal(license)
+}
+
+// decodePayload returns the bytes the signature covers and the license they
+// describe.
+func decodePayload(signedLicense *SignedLicense) ([]byte, *License, error) {
+	switch signedLicense.Format {
+	case "", FormatJSON:
+		return decodeJSONPayload(signedLicense)
+	case FormatJWS:
+		return decodeJWSPayload(signedLicense)
+	case FormatCOSE:
+		return decodeCOSEPayload(signedLicense)
+	case FormatLicenseKey:
+		return decodeLicenseKeyPayload(signedLicense)
+	default:
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, signedLicense.Format)
+	}
+}
+
+// decodeJSONPayload handles the default format, where the signature covers
+// the payload itself. Licenses issued before payloads were stored carry no
+// payload; their bytes are rebuilt by marshalling Data as earlier releases did.
+func decodeJSONPayload(signedLicense *SignedLicense) ([]byte, *License, error) {
+	if signedLicense.Payload == "" {
+		data, err := encodePayload(&signedLicense.Data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal license data: %w", err)
+		}
+
+		return data, &signedLicense.Data, nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(signedLicense.Payload)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: payload is not valid base64", ErrInvalidPayload)
+	}
+
+	var license License
+	if err := json.Unmarshal(data, &license); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidPayload, err)
+	}
+
+	return data, &license, nil
+}
+
+// sameLicense reports whether two licenses carry the same claims.
+func sameLicense(a, b *License) bool {
+	x, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+
+	y, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(x, y)
+}
+
+// licenseJSON has the fields of License without its JSON methods.
+type licenseJSON License
+
+// licenseFields holds the lower-cased JSON names of the known License fields.
+var licenseFields = func() map[string]bool {
+	fields := make(map[string]bool)
+
+	t := reflect.TypeFor[License]()
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[strings.ToLower(name)] = true
+		}
+	}
+
+	return fields
+}()
+
+// isLicenseField reports whether name is a known License field. Matching is
+// case-insensitive, like encoding/json.
+func isLicenseField(name string) bool {
+	return licenseFields[strings.ToLower(name)]
+}
+
+// MarshalJSON encodes the known fields in declaration order followed by the
+// extensions sorted by name. A license without extensions encodes exactly as
+// it did before extensions existed.
+func (l License) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(licenseJSON(l))
+	if err != nil || len(l.Extensions) == 0 {
+		return data, err
+	}
+
+	var buf bytes.Buffer
+
+	buf.Write(data[:len(data)-1])
+
+	for _, name := range slices.Sorted(maps.Keys(l.Extensions)) {
+		if isLicenseField(name) {
+			return nil, fmt.Errorf("license extension %q shadows a known field", name)
+		}
+
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.WriteByte(',')
+		buf.Write(key)
+		buf.WriteByte(':')
+
+		if err := json.Compact(&buf, l.Extensions[name]); err != nil {
+			return nil, fmt.Errorf("license extension %q: %w", name, err)
+		}
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes the known fields and keeps every other top-level
+// field in Extensions.
+func (l *License) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+
+	var license licenseJSON
+	if err := json.Unmarshal(data, &license); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	maps.DeleteFunc(fields, func(name string, _ json.RawMessage) bool { return isLicenseField(name) })
+
+	license.Extensions = nil
+	if len(fields) > 0 {
+		license.Extensions = fields
+	}
+
+	*l = License(license)
+
+	return nil
+}
@@ -0,0 +1,212 @@
+/*******************************************************************
+
+		::          ::        +--------+-----------------------+
+		  ::      ::          | Author | Dmitry Novikov        |
+		::::::::::::::        | Email  | dredfort.42@gmail.com |
+	  ::::  ::::::  ::::      +--------+-----------------------+
+	::::::::::::::::::::::
+	::  ::::::::::::::  ::    File     | payload_test.go
+	::  ::          ::  ::    Created  | 2026-10-16
+		  ::::  ::::          Modified | 2026-10-16
+
+	GitHub:   https://github.com/dredfort42
+	LinkedIn: https://linkedin.com/in/novikov-da
+
+*******************************************************************/
+
+package licenser_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	licenser "github.com/dredfort42/go_licenser"
+)
+
+func TestSignedPayload(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	signer, err := licenser.NewSigner(key, licenser.AlgorithmEdDSA)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+
+	manager, err := licenser.NewManager(licenser.Config{GeneratorMode: true, Signer: signer})
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	license := licenser.License{
+		Customer: "Payload Customer",
+		AppID:    "payload-app",
+		Services: []licenser.Service{{ID: "test", Name: "Test"}},
+		Limits:   map[string]int{"seats": 10},
+	}
+
+	signedLicense, err := manager.GenerateLicense(&license)
+	if err != nil {
+		t.Fatalf("Failed to generate license: %v", err)
+	}
+
+	t.Run("PayloadIsSignedBytes", func(t *testing.T) {
+		payload, err := base64.StdEncoding.DecodeString(signedLicense.Payload)
+		if err != nil {
+			t.Fatalf("Failed to decode payload: %v", err)
+		}
+
+		signature, err := base64.StdEncoding.DecodeString(signedLicense.Signature)
+		if err != nil {
+			t.Fatalf("Failed to decode signature: %v", err)
+		}
+
+		if !ed25519.Verify(key.Public().(ed25519.PublicKey), payload, signature) {
+			t.Error("Signature should cover the stored payload bytes")
+		}
+	})
+
+	t.Run("ForeignEncoding", func(t *testing.T) {
+		// Key order and whitespace differ from what json.Marshal produces.
+		payload := []byte(`{ "services": [{"name": "Test", "id": "test"}], "app_id": "payload-app",
+			"customer": "Payload Customer", "issued_at": 1760000000 }`)
+
+		signature, err := signer.Sign(payload)
+		if err != nil {
+			t.Fatalf("Failed to sign payload: %v", err)
+		}
+
+		foreign := &licenser.SignedLicense{
+			Payload:   base64.StdEncoding.EncodeToString(payload),
+			Signature: base64.StdEncoding.EncodeToString(signature),
+			KeyID:     manager.KeyID(),
+			Algorithm: licenser.AlgorithmEdDSA,
+		}
+
+		if err := json.Unmarshal(payload, &foreign.Data); err != nil {
+			t.Fatalf("Failed to unmarshal payload: %v", err)
+		}
+
+		result := manager.ValidateLicense(foreign)
+		if !result.Valid {
+			t.Errorf("License with a foreign payload encoding should be valid, errors: %v", result.Errors)
+		}
+	})
+
+	t.Run("TamperedData", func(t *testing.T) {
+		tampered := *signedLicense
+		tampered.Data.Limits = map[string]int{"seats": 1000}
+
+		result := manager.ValidateLicense(&tampered)
+		if result.Valid {
+			t.Error("License with data that differs from the payload should be invalid")
+		}
+
+		if len(result.Errors) == 0 || !contains(result.Errors[0], licenser.ErrPayloadMismatch.Error()) {
+			t.Errorf("Expected payload mismatch error, got %v", result.Errors)
+		}
+	})
+
+	t.Run("InvalidPayload", func(t *testing.T) {
+		invalid := *signedLicense
+		invalid.Payload = "not base64!"
+
+		result := manager.ValidateLicense(&invalid)
+		if result.Valid || len(result.Errors) == 0 || !contains(result.Errors[0], licenser.ErrInvalidPayload.Error()) {
+			t.Errorf("Expected invalid payload error, got %v", result.Errors)
+		}
+	})
+
+	t.Run("LegacyLicense", func(t *testing.T) {
+		legacy := *signedLicense
+		legacy.Payload = ""
+
+		result := manager.ValidateLicense(&legacy)
+		if !result.Valid {
+			t.Errorf("License without a payload should be verified against its data, errors: %v", result.Errors)
+		}
+	})
+}
+
+func TestLicenseExtensions(t *testing.T) {
+	manager := newEd25519Manager(t, licenser.Config{})
+
+	t.Run("NewerIssuer", func(t *testing.T) {
+		// A newer issuer added "max_nodes" and a nested "support" claim.
+		issuer := newEd25519Manager(t, licenser.Config{})
+		license := licenser.License{
+			Customer: "Forward Customer",
+			AppID:    "forward-app",
+			Services: []licenser.Service{{ID: "test", Name: "Test"}},
+			Extensions: map[string]json.RawMessage{
+				"max_nodes": json.RawMessage(`5`),
+				"support":   json.RawMessage(`{"tier": "gold"}`),
+			},
+		}
+
+		signedLicense, err := issuer.GenerateLicense(&license)
+		if err != nil {
+			t.Fatalf("Failed to generate license: %v", err)
+		}
+
+		data, err := json.Marshal(signedLicense)
+		if err != nil {
+			t.Fatalf("Failed to marshal license: %v", err)
+		}
+
+		var loaded licenser.SignedLicense
+		if err := json.Unmarshal(data, &loaded); err != nil {
+			t.Fatalf("Failed to unmarshal license: %v", err)
+		}
+
+		validator, err := licenser.NewManager(licenser.Config{PublicKeyPEM: exportPublicKey(t, issuer)})
+		if err != nil {
+			t.Fatalf("Failed to create validator: %v", err)
+		}
+
+		result := validator.ValidateLicense(&loaded)
+		if !result.Valid {
+			t.Errorf("License with unknown claims should be valid, errors: %v", result.Errors)
+		}
+
+		if string(loaded.Data.Extensions["max_nodes"]) != "5" {
+			t.Errorf("Expected extension 'max_nodes' to be 5, got %s", loaded.Data.Extensions["max_nodes"])
+		}
+
+		if string(loaded.Data.Extensions["support"]) != `{"tier":"gold"}` {
+			t.Errorf("Expected extension 'support', got %s", loaded.Data.Extensions["support"])
+		}
+	})
+
+	t.Run("KnownFieldsAreNotExtensions", func(t *testing.T) {
+		var license licenser.License
+		if err := json.Unmarshal([]byte(`{"customer":"A","App_ID":"b","issued_at":1}`), &license); err != nil {
+			t.Fatalf("Failed to unmarshal license: %v", err)
+		}
+
+		if license.Extensions != nil {
+			t.Errorf("Expected no extensions, got %v", license.Extensions)
+		}
+
+		if license.AppID != "b" {
+			t.Errorf("Expected app ID 'b', got '%s'", license.AppID)
+		}
+	})
+
+	t.Run("ShadowedField", func(t *testing.T) {
+		license := licenser.License{
+			Customer:   "Shadow Customer",
+			AppID:      "shadow-app",
+			Services:   []licenser.Service{{ID: "test", Name: "Test"}},
+			Extensions: map[string]json.RawMessage{"customer": json.RawMessage(`"Someone Else"`)},
+		}
+
+		if _, err := manager.GenerateLicense(&license); err == nil {
+			t.Error("Expected an error for an extension that shadows a known field")
+		}
+	})
+}
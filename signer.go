@@ -0,0 +1,301 @@
+/*******************************************************************
+
+		::          ::        +--------+-----------------------+
+		  ::      ::          | Author | Dmitry Novikov        |
+		::::::::::::::        | Email  | dredfort.42@gmail.com |
+	  ::::  ::::::  ::::      +--------+-----------------------+
+	::::::::::::::::::::::
+	::  ::::::::::::::  ::    File     | signer.go
+	::  ::          ::  ::    Created  | 2026-10-16
+		  ::::  ::::          Modified | 2026-10-16
+
+	GitHub:   https://github.com/dredfort42
+	LinkedIn: https://linkedin.com/in/novikov-da
+
+*******************************************************************/
+
+package licenser
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+)
+
+// Signer signs license payloads.
+//
+// Any crypto.Signer, including keys held by an HSM or a cloud KMS, can be
+// adapted with NewSigner.
+type Signer interface {
+	Algorithm() string                // Signing algorithm name, e.g. "RS256"
+	Public() crypto.PublicKey         // Public key matching the signing key
+	Sign(data []byte) ([]byte, error) // Signs data and returns the raw signature
+}
+
+// SignatureVerifier verifies license signatures.
+type SignatureVerifier interface {
+	Algorithm() string                   // Signing algorithm name, e.g. "RS256"
+	Verify(data, signature []byte) error // Verifies a raw signature over data
+}
+
+// NewSigner creates a Signer for the given algorithm backed by key.
+func NewSigner(key crypto.Signer, algorithm string) (Signer, error) {
+	if key == nil {
+		return nil, ErrInvalidPrivateKey
+	}
+
+	if err := checkKeyAlgorithm(key.Public(), algorithm); err != nil {
+		return nil, err
+	}
+
+	return &cryptoSigner{key: key, algorithm: algorithm}, nil
+}
+
+// NewSignatureVerifier creates a SignatureVerifier for the given algorithm backed by publicKey.
+func NewSignatureVerifier(publicKey crypto.PublicKey, algorithm string) (SignatureVerifier, error) {
+	if err := checkKeyAlgorithm(publicKey, algorithm); err != nil {
+		return nil, err
+	}
+
+	switch pub := publicKey.(type) {
+	case *rsa.PublicKey:
+		if algorithm == AlgorithmPS256 {
+			return &rsaPSSVerifier{publicKey: pub}, nil
+		}
+
+		return &rsaPKCS1Verifier{publicKey: pub}, nil
+	case ed25519.PublicKey:
+		return &ed25519Verifier{publicKey: pub}, nil
+	case *ecdsa.PublicKey:
+		return &ecdsaVerifier{publicKey: pub, algorithm: algorithm}, nil
+	default:
+		return nil, ErrInvalidPublicKey
+	}
+}
+
+// checkKeyAlgorithm reports whether publicKey can be used with algorithm.
+func checkKeyAlgorithm(publicKey crypto.PublicKey, algorithm string) error {
+	var ok bool
+
+	switch algorithm {
+	case AlgorithmRS256, AlgorithmPS256:
+		_, ok = publicKey.(*rsa.PublicKey)
+	case AlgorithmEdDSA:
+		_, ok = publicKey.(ed25519.PublicKey)
+	case AlgorithmES256, AlgorithmES384:
+		ecPub, isEC := publicKey.(*ecdsa.PublicKey)
+		ok = isEC && ecPub.Curve == curveForAlgorithm(algorithm)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, algorithm)
+	}
+
+	if !ok {
+		return fmt.Errorf("%w: key type does not match %s", ErrInvalidPublicKey, algorithm)
+	}
+
+	return nil
+}
+
+// hashForAlgorithm returns the digest used by algorithm, or zero if the message is signed directly.
+func hashForAlgorithm(algorithm string) crypto.Hash {
+	switch algorithm {
+	case AlgorithmEdDSA:
+		return crypto.Hash(0)
+	case AlgorithmES384:
+		return crypto.SHA384
+	default:
+		return crypto.SHA256
+	}
+}
+
+// curveForAlgorithm returns the elliptic curve used by an ECDSA algorithm.
+func curveForAlgorithm(algorithm string) elliptic.Curve {
+	switch algorithm {
+	case AlgorithmES256:
+		return elliptic.P256()
+	case AlgorithmES384:
+		return elliptic.P384()
+	default:
+		return nil
+	}
+}
+
+// digest hashes data with h, or returns data unchanged when h is zero.
+func digest(h crypto.Hash, data []byte) []byte {
+	if h == 0 {
+		return data
+	}
+
+	hasher := h.New()
+	hasher.Write(data)
+
+	return hasher.Sum(nil)
+}
+
+// cryptoSigner adapts a crypto.Signer to the Signer interface.
+type cryptoSigner struct {
+	key       crypto.Signer
+	algorithm string
+}
+
+func (s *cryptoSigner) Algorithm() string {
+	return s.algorithm
+}
+
+func (s *cryptoSigner) Public() crypto.PublicKey {
+	return s.key.Public()
+}
+
+func (s *cryptoSigner) Sign(data []byte) ([]byte, error) {
+	h := hashForAlgorithm(s.algorithm)
+
+	var opts crypto.SignerOpts = h
+	if s.algorithm == AlgorithmPS256 {
+		opts = pssOptions
+	}
+
+	signature, err := s.key.Sign(rand.Reader, digest(h, data), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if ecPub, ok := s.key.Public().(*ecdsa.PublicKey); ok {
+		// crypto.Signer returns ASN.1 DER; licenses carry the fixed-size
+		// r || s form used by JWS and COSE.
+		return ecdsaDERToRaw(signature, ecPub.Curve)
+	}
+
+	return signature, nil
+}
+
+// signLegacy signs the payload of a JSON-format license. RS256 with an
+// in-process RSA key signs the bare SHA-256 digest without the DigestInfo
+// prefix, as 1.0.x did, so validators already in the field accept new
+// licenses. Other signers, including HSM and cloud-KMS keys that refuse to
+// sign without a hash, sign as usual.
+func signLegacy(signer Signer, data []byte) ([]byte, error) {
+	s, ok := signer.(*cryptoSigner)
+	if !ok || s.algorithm != AlgorithmRS256 {
+		return signer.Sign(data)
+	}
+
+	key, ok := s.key.(*rsa.PrivateKey)
+	if !ok {
+		return signer.Sign(data)
+	}
+
+	hash := sha256.Sum256(data)
+
+	return rsa.SignPKCS1v15(rand.Reader, key, crypto.Hash(0), hash[:])
+}
+
+// pssOptions are the RSASSA-PSS parameters used by PS256.
+var pssOptions = &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: crypto.SHA256}
+
+// rsaPKCS1Verifier verifies RSASSA-PKCS1-v1_5 signatures with SHA-256.
+type rsaPKCS1Verifier struct {
+	publicKey *rsa.PublicKey
+}
+
+func (v *rsaPKCS1Verifier) Algorithm() string {
+	return AlgorithmRS256
+}
+
+func (v *rsaPKCS1Verifier) Verify(data, signature []byte) error {
+	hash := sha256.Sum256(data)
+
+	if err := rsa.VerifyPKCS1v15(v.publicKey, crypto.SHA256, hash[:], signature); err == nil {
+		return nil
+	}
+
+	// JSON-format RS256 licenses are signed over the bare digest without the
+	// DigestInfo prefix, as 1.0.x did; see signLegacy.
+	return rsa.VerifyPKCS1v15(v.publicKey, 0, hash[:], signature)
+}
+
+// rsaPSSVerifier verifies RSASSA-PSS signatures with SHA-256.
+type rsaPSSVerifier struct {
+	publicKey *rsa.PublicKey
+}
+
+func (v *rsaPSSVerifier) Algorithm() string {
+	return AlgorithmPS256
+}
+
+func (v *rsaPSSVerifier) Verify(data, signature []byte) error {
+	hash := sha256.Sum256(data)
+
+	return rsa.VerifyPSS(v.publicKey, crypto.SHA256, hash[:], signature, pssOptions)
+}
+
+// ed25519Verifier verifies Ed25519 signatures.
+type ed25519Verifier struct {
+	publicKey ed25519.PublicKey
+}
+
+func (v *ed25519Verifier) Algorithm() string {
+	return AlgorithmEdDSA
+}
+
+func (v *ed25519Verifier) Verify(data, signature []byte) error {
+	if !ed25519.Verify(v.publicKey, data, signature) {
+		return ErrSignatureVerification
+	}
+
+	return nil
+}
+
+// ecdsaVerifier verifies ECDSA signatures in r || s form.
+type ecdsaVerifier struct {
+	publicKey *ecdsa.PublicKey
+	algorithm string
+}
+
+func (v *ecdsaVerifier) Algorithm() string {
+	return v.algorithm
+}
+
+func (v *ecdsaVerifier) Verify(data, signature []byte) error {
+	size := curveByteSize(v.publicKey.Curve)
+	if len(signature) != 2*size {
+		return ErrInvalidSignature
+	}
+
+	r := new(big.Int).SetBytes(signature[:size])
+	s := new(big.Int).SetBytes(signature[size:])
+
+	if !ecdsa.Verify(v.publicKey, digest(hashForAlgorithm(v.algorithm), data), r, s) {
+		return ErrSignatureVerification
+	}
+
+	return nil
+}
+
+// ecdsaDERToRaw converts an ASN.1 DER ECDSA signature to fixed-size r || s.
+func ecdsaDERToRaw(der []byte, curve elliptic.Curve) ([]byte, error) {
+	var sig struct {
+		R, S *big.Int
+	}
+
+	if _, err := asn1.Unmarshal(der, &sig); err != nil {
+		return nil, fmt.Errorf("failed to parse ECDSA signature: %w", err)
+	}
+
+	size := curveByteSize(curve)
+	raw := make([]byte, 2*size)
+	sig.R.FillBytes(raw[:size])
+	sig.S.FillBytes(raw[size:])
+
+	return raw, nil
+}
+
+func curveByteSize(curve elliptic.Curve) int {
+	return (curve.Params().BitSize + 7) / 8
+}
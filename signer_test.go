@@ -0,0 +1,674 @@
+/*******************************************************************
+
+		::          ::        +--------+-----------------------+
+		  ::      ::          | Author | Dmitry Novikov        |
+		::::::::::::::        | Email  | dredfort.42@gmail.com |
+	  ::::  ::::::  ::::      +--------+-----------------------+
+	::::::::::::::::::::::
+	::  ::::::::::::::  ::    File     | signer_test.go
+	::  ::          ::  ::    Created  | 2026-10-16
+		  ::::  ::::          Modified | 2026-10-16
+
+	GitHub:   https://github.com/dredfort42
+	LinkedIn: https://linkedin.com/in/novikov-da
+
+*******************************************************************/
+
+package licenser_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	licenser "github.com/dredfort42/go_licenser"
+)
+
+// countingSigner wraps a Signer and records how often it was used.
+type countingSigner struct {
+	licenser.Signer
+	calls int
+}
+
+func (s *countingSigner) Sign(data []byte) ([]byte, error) {
+	s.calls++
+
+	return s.Signer.Sign(data)
+}
+
+// hashingKey is an RSA key that, like many HSM and cloud-KMS keys, refuses
+// to sign without a hash.
+type hashingKey struct {
+	*rsa.PrivateKey
+}
+
+func (k hashingKey) Sign(random io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts.HashFunc() == 0 {
+		return nil, errors.New("hash required")
+	}
+
+	return k.PrivateKey.Sign(random, digest, opts)
+}
+
+func TestPluggableSigner(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	t.Run("CustomSigner", func(t *testing.T) {
+		signer, err := licenser.NewSigner(key, licenser.AlgorithmRS256)
+		if err != nil {
+			t.Fatalf("Failed to create signer: %v", err)
+		}
+
+		custom := &countingSigner{Signer: signer}
+
+		manager, err := licenser.NewManager(licenser.Config{
+			GeneratorMode: true,
+			Signer:        custom,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create manager: %v", err)
+		}
+
+		license := licenser.License{
+			Customer: "Test Customer",
+			AppID:    "test-app",
+			Services: []licenser.Service{{ID: "test", Name: "Test"}},
+		}
+
+		signedLicense, err := manager.GenerateLicense(&license)
+		if err != nil {
+			t.Fatalf("Failed to generate license: %v", err)
+		}
+
+		if custom.calls != 1 {
+			t.Errorf("Expected custom signer to be called once, got %d", custom.calls)
+		}
+
+		if signedLicense.Algorithm != licenser.AlgorithmRS256 {
+			t.Errorf("Expected algorithm '%s', got '%s'", licenser.AlgorithmRS256, signedLicense.Algorithm)
+		}
+
+		result := manager.ValidateLicense(signedLicense)
+		if !result.Valid {
+			t.Errorf("License should be valid, errors: %v", result.Errors)
+		}
+	})
+
+	t.Run("CustomVerifier", func(t *testing.T) {
+		verifier, err := licenser.NewSignatureVerifier(&key.PublicKey, licenser.AlgorithmRS256)
+		if err != nil {
+			t.Fatalf("Failed to create verifier: %v", err)
+		}
+
+		manager, err := licenser.NewManager(licenser.Config{Verifier: verifier})
+		if err != nil {
+			t.Fatalf("Failed to create manager with custom verifier: %v", err)
+		}
+
+		signer, err := licenser.NewSigner(key, licenser.AlgorithmRS256)
+		if err != nil {
+			t.Fatalf("Failed to create signer: %v", err)
+		}
+
+		issuer, err := licenser.NewManager(licenser.Config{GeneratorMode: true, Signer: signer})
+		if err != nil {
+			t.Fatalf("Failed to create issuer: %v", err)
+		}
+
+		license := licenser.License{
+			Customer: "Test Customer",
+			AppID:    "test-app",
+			Services: []licenser.Service{{ID: "test", Name: "Test"}},
+		}
+
+		signedLicense, err := issuer.GenerateLicense(&license)
+		if err != nil {
+			t.Fatalf("Failed to generate license: %v", err)
+		}
+
+		result := manager.ValidateLicense(signedLicense)
+		if !result.Valid {
+			t.Errorf("License should be valid, errors: %v", result.Errors)
+		}
+	})
+
+	t.Run("UnsupportedAlgorithm", func(t *testing.T) {
+		_, err := licenser.NewSigner(key, "HS256")
+		if !errors.Is(err, licenser.ErrUnsupportedAlgorithm) {
+			t.Errorf("Expected ErrUnsupportedAlgorithm, got %v", err)
+		}
+
+		_, err = licenser.NewManager(licenser.Config{GeneratorMode: true, KeySize: 1024, Algorithm: "HS256"})
+		if !errors.Is(err, licenser.ErrUnsupportedAlgorithm) {
+			t.Errorf("Expected ErrUnsupportedAlgorithm, got %v", err)
+		}
+	})
+
+	t.Run("LegacySignature", func(t *testing.T) {
+		signer, err := licenser.NewSigner(key, licenser.AlgorithmRS256)
+		if err != nil {
+			t.Fatalf("Failed to create signer: %v", err)
+		}
+
+		manager, err := licenser.NewManager(licenser.Config{GeneratorMode: true, Signer: signer})
+		if err != nil {
+			t.Fatalf("Failed to create manager: %v", err)
+		}
+
+		license := licenser.License{
+			Customer: "Legacy Customer",
+			AppID:    "legacy-app",
+			Services: []licenser.Service{{ID: "test", Name: "Test"}},
+			IssuedAt: time.Now().Unix(),
+		}
+
+		data, err := json.Marshal(license)
+		if err != nil {
+			t.Fatalf("Failed to marshal license: %v", err)
+		}
+
+		// Older releases signed the bare SHA-256 digest.
+		hash := sha256.Sum256(data)
+
+		signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.Hash(0), hash[:])
+		if err != nil {
+			t.Fatalf("Failed to sign license: %v", err)
+		}
+
+		signedLicense := &licenser.SignedLicense{
+			Data:      license,
+			Signature: base64.StdEncoding.EncodeToString(signature),
+			Algorithm: licenser.AlgorithmRS256,
+			CreatedAt: time.Now().Unix(),
+		}
+
+		result := manager.ValidateLicense(signedLicense)
+		if !result.Valid {
+			t.Errorf("Legacy license should be valid, errors: %v", result.Errors)
+		}
+	})
+
+	t.Run("LegacyValidator", func(t *testing.T) {
+		signer, err := licenser.NewSigner(key, licenser.AlgorithmRS256)
+		if err != nil {
+			t.Fatalf("Failed to create signer: %v", err)
+		}
+
+		manager, err := licenser.NewManager(licenser.Config{GeneratorMode: true, Signer: signer})
+		if err != nil {
+			t.Fatalf("Failed to create manager: %v", err)
+		}
+
+		license := licenser.License{
+			Customer: "Upgrade Customer",
+			AppID:    "upgrade-app",
+			Services: []licenser.Service{{ID: "test", Name: "Test"}},
+		}
+
+		signedLicense, err := manager.GenerateLicense(&license)
+		if err != nil {
+			t.Fatalf("Failed to generate license: %v", err)
+		}
+
+		data, err := json.Marshal(signedLicense.Data)
+		if err != nil {
+			t.Fatalf("Failed to marshal license: %v", err)
+		}
+
+		signature, err := base64.StdEncoding.DecodeString(signedLicense.Signature)
+		if err != nil {
+			t.Fatalf("Failed to decode signature: %v", err)
+		}
+
+		// 1.0.x validators verify the bare SHA-256 digest.
+		hash := sha256.Sum256(data)
+		if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.Hash(0), hash[:], signature); err != nil {
+			t.Errorf("RS256 license should verify with the 1.0.x check: %v", err)
+		}
+	})
+
+	t.Run("StandardToken", func(t *testing.T) {
+		signer, err := licenser.NewSigner(key, licenser.AlgorithmRS256)
+		if err != nil {
+			t.Fatalf("Failed to create signer: %v", err)
+		}
+
+		manager, err := licenser.NewManager(licenser.Config{GeneratorMode: true, Signer: signer})
+		if err != nil {
+			t.Fatalf("Failed to create manager: %v", err)
+		}
+
+		token, err := manager.GenerateToken(&licenser.License{
+			Customer: "Token Customer",
+			AppID:    "token-app",
+			Services: []licenser.Service{{ID: "test", Name: "Test"}},
+		})
+		if err != nil {
+			t.Fatalf("Failed to generate token: %v", err)
+		}
+
+		dot := strings.LastIndex(token, ".")
+
+		signature, err := base64.RawURLEncoding.DecodeString(token[dot+1:])
+		if err != nil {
+			t.Fatalf("Failed to decode signature: %v", err)
+		}
+
+		// Tokens use standard RS256 (RFC 7518), with the DigestInfo prefix.
+		hash := sha256.Sum256([]byte(token[:dot]))
+		if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, hash[:], signature); err != nil {
+			t.Errorf("RS256 token should verify with crypto.SHA256: %v", err)
+		}
+	})
+
+	t.Run("HashingKey", func(t *testing.T) {
+		signer, err := licenser.NewSigner(hashingKey{key}, licenser.AlgorithmRS256)
+		if err != nil {
+			t.Fatalf("Failed to create signer: %v", err)
+		}
+
+		manager, err := licenser.NewManager(licenser.Config{GeneratorMode: true, Signer: signer})
+		if err != nil {
+			t.Fatalf("Failed to create manager: %v", err)
+		}
+
+		signedLicense, err := manager.GenerateLicense(&licenser.License{
+			Customer: "KMS Customer",
+			AppID:    "kms-app",
+			Services: []licenser.Service{{ID: "test", Name: "Test"}},
+			Version:  "1.0.0",
+		})
+		if err != nil {
+			t.Fatalf("Failed to generate license with a hashing key: %v", err)
+		}
+
+		if result := manager.ValidateLicense(signedLicense); !result.Valid {
+			t.Errorf("License should be valid, errors: %v", result.Errors)
+		}
+	})
+}
+
+func TestEd25519(t *testing.T) {
+	manager, err := licenser.NewManager(licenser.Config{
+		GeneratorMode: true,
+		Algorithm:     licenser.AlgorithmEdDSA,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	license := licenser.License{
+		Customer: "Ed25519 Customer",
+		AppID:    "ed25519-app",
+		Services: []licenser.Service{{ID: "test", Name: "Test"}},
+	}
+
+	signedLicense, err := manager.GenerateLicense(&license)
+	if err != nil {
+		t.Fatalf("Failed to generate license: %v", err)
+	}
+
+	t.Run("GenerateAndValidate", func(t *testing.T) {
+		if signedLicense.Algorithm != licenser.AlgorithmEdDSA {
+			t.Errorf("Expected algorithm '%s', got '%s'", licenser.AlgorithmEdDSA, signedLicense.Algorithm)
+		}
+
+		signature, err := base64.StdEncoding.DecodeString(signedLicense.Signature)
+		if err != nil {
+			t.Fatalf("Failed to decode signature: %v", err)
+		}
+
+		if len(signature) != 64 {
+			t.Errorf("Expected 64 byte signature, got %d", len(signature))
+		}
+
+		result := manager.ValidateLicense(signedLicense)
+		if !result.Valid {
+			t.Errorf("License should be valid, errors: %v", result.Errors)
+		}
+
+		tampered := *signedLicense
+		tampered.Data.Customer = "Tampered Customer"
+
+		if manager.ValidateLicense(&tampered).Valid {
+			t.Error("Tampered license should be invalid")
+		}
+	})
+
+	t.Run("ExportAndReload", func(t *testing.T) {
+		privateKeyPEM, publicKeyPEM, err := manager.ExportKeys()
+		if err != nil {
+			t.Fatalf("Failed to export keys: %v", err)
+		}
+
+		if !contains(privateKeyPEM, "BEGIN PRIVATE KEY") {
+			t.Error("Private key should be PKCS#8 encoded")
+		}
+
+		// Algorithm is derived from the key type when not configured.
+		generator, err := licenser.NewManager(licenser.Config{
+			PrivateKeyPEM: privateKeyPEM,
+			GeneratorMode: true,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create manager from exported key: %v", err)
+		}
+
+		reissued, err := generator.GenerateLicense(&license)
+		if err != nil {
+			t.Fatalf("Failed to generate license: %v", err)
+		}
+
+		if reissued.Algorithm != licenser.AlgorithmEdDSA {
+			t.Errorf("Expected algorithm '%s', got '%s'", licenser.AlgorithmEdDSA, reissued.Algorithm)
+		}
+
+		validator, err := licenser.NewManager(licenser.Config{PublicKeyPEM: publicKeyPEM})
+		if err != nil {
+			t.Fatalf("Failed to create validator: %v", err)
+		}
+
+		result := validator.ValidateLicense(signedLicense)
+		if !result.Valid {
+			t.Errorf("License should be valid, errors: %v", result.Errors)
+		}
+
+		if validator.GetPublicKey() != nil {
+			t.Error("GetPublicKey should be nil for Ed25519 keys")
+		}
+
+		if validator.PublicKey() == nil {
+			t.Error("PublicKey should not be nil")
+		}
+	})
+}
+
+func TestECDSA(t *testing.T) {
+	tests := []struct {
+		algorithm     string
+		signatureSize int
+	}{
+		{licenser.AlgorithmES256, 64},
+		{licenser.AlgorithmES384, 96},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			manager, err := licenser.NewManager(licenser.Config{
+				GeneratorMode: true,
+				Algorithm:     tt.algorithm,
+			})
+			if err != nil {
+				t.Fatalf("Failed to create manager: %v", err)
+			}
+
+			license := licenser.License{
+				Customer: "ECDSA Customer",
+				AppID:    "ecdsa-app",
+				Services: []licenser.Service{{ID: "test", Name: "Test"}},
+			}
+
+			signedLicense, err := manager.GenerateLicense(&license)
+			if err != nil {
+				t.Fatalf("Failed to generate license: %v", err)
+			}
+
+			if signedLicense.Algorithm != tt.algorithm {
+				t.Errorf("Expected algorithm '%s', got '%s'", tt.algorithm, signedLicense.Algorithm)
+			}
+
+			signature, err := base64.StdEncoding.DecodeString(signedLicense.Signature)
+			if err != nil {
+				t.Fatalf("Failed to decode signature: %v", err)
+			}
+
+			if len(signature) != tt.signatureSize {
+				t.Errorf("Expected %d byte signature, got %d", tt.signatureSize, len(signature))
+			}
+
+			privateKeyPEM, publicKeyPEM, err := manager.ExportKeys()
+			if err != nil {
+				t.Fatalf("Failed to export keys: %v", err)
+			}
+
+			if !contains(privateKeyPEM, "BEGIN EC PRIVATE KEY") {
+				t.Error("Private key should be SEC1 encoded")
+			}
+
+			generator, err := licenser.NewManager(licenser.Config{
+				PrivateKeyPEM: privateKeyPEM,
+				GeneratorMode: true,
+			})
+			if err != nil {
+				t.Fatalf("Failed to create manager from exported key: %v", err)
+			}
+
+			reissued, err := generator.GenerateLicense(&license)
+			if err != nil {
+				t.Fatalf("Failed to generate license: %v", err)
+			}
+
+			if reissued.Algorithm != tt.algorithm {
+				t.Errorf("Expected algorithm '%s', got '%s'", tt.algorithm, reissued.Algorithm)
+			}
+
+			validator, err := licenser.NewManager(licenser.Config{PublicKeyPEM: publicKeyPEM})
+			if err != nil {
+				t.Fatalf("Failed to create validator: %v", err)
+			}
+
+			result := validator.ValidateLicense(signedLicense)
+			if !result.Valid {
+				t.Errorf("License should be valid, errors: %v", result.Errors)
+			}
+
+			signedLicense.Data.AppID = "tampered-app"
+
+			if validator.ValidateLicense(signedLicense).Valid {
+				t.Error("Tampered license should be invalid")
+			}
+		})
+	}
+
+	t.Run("CurveMismatch", func(t *testing.T) {
+		manager, err := licenser.NewManager(licenser.Config{
+			GeneratorMode: true,
+			Algorithm:     licenser.AlgorithmES256,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create manager: %v", err)
+		}
+
+		_, err = licenser.NewSignatureVerifier(manager.PublicKey(), licenser.AlgorithmES384)
+		if err == nil {
+			t.Error("Expected error for P-256 key used with ES384")
+		}
+	})
+}
+
+func TestRSAPSS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	newManager := func(t *testing.T, algorithm string) *licenser.Manager {
+		t.Helper()
+
+		signer, err := licenser.NewSigner(key, algorithm)
+		if err != nil {
+			t.Fatalf("Failed to create signer: %v", err)
+		}
+
+		manager, err := licenser.NewManager(licenser.Config{GeneratorMode: true, Signer: signer})
+		if err != nil {
+			t.Fatalf("Failed to create manager: %v", err)
+		}
+
+		return manager
+	}
+
+	license := licenser.License{
+		Customer: "PSS Customer",
+		AppID:    "pss-app",
+		Services: []licenser.Service{{ID: "test", Name: "Test"}},
+	}
+
+	legacyManager := newManager(t, licenser.AlgorithmRS256)
+
+	legacyLicense, err := legacyManager.GenerateLicense(&license)
+	if err != nil {
+		t.Fatalf("Failed to generate RS256 license: %v", err)
+	}
+
+	pssManager := newManager(t, licenser.AlgorithmPS256)
+
+	pssLicense, err := pssManager.GenerateLicense(&license)
+	if err != nil {
+		t.Fatalf("Failed to generate PS256 license: %v", err)
+	}
+
+	t.Run("Algorithm", func(t *testing.T) {
+		if pssLicense.Algorithm != licenser.AlgorithmPS256 {
+			t.Errorf("Expected algorithm '%s', got '%s'", licenser.AlgorithmPS256, pssLicense.Algorithm)
+		}
+	})
+
+	t.Run("ValidatePS256", func(t *testing.T) {
+		result := pssManager.ValidateLicense(pssLicense)
+		if !result.Valid {
+			t.Errorf("PS256 license should be valid, errors: %v", result.Errors)
+		}
+	})
+
+	t.Run("ValidateDeployedRS256", func(t *testing.T) {
+		result := pssManager.ValidateLicense(legacyLicense)
+		if !result.Valid {
+			t.Errorf("RS256 license should still be valid, errors: %v", result.Errors)
+		}
+	})
+
+	t.Run("ConfiguredAlgorithm", func(t *testing.T) {
+		manager, err := licenser.NewManager(licenser.Config{
+			GeneratorMode: true,
+			KeySize:       1024,
+			Algorithm:     licenser.AlgorithmPS256,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create manager: %v", err)
+		}
+
+		signedLicense, err := manager.GenerateLicense(&license)
+		if err != nil {
+			t.Fatalf("Failed to generate license: %v", err)
+		}
+
+		if signedLicense.Algorithm != licenser.AlgorithmPS256 {
+			t.Errorf("Expected algorithm '%s', got '%s'", licenser.AlgorithmPS256, signedLicense.Algorithm)
+		}
+	})
+}
+
+func TestAlgorithmEnforcement(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	signer, err := licenser.NewSigner(key, licenser.AlgorithmRS256)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+
+	manager, err := licenser.NewManager(licenser.Config{GeneratorMode: true, Signer: signer})
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	license := licenser.License{
+		Customer: "Test Customer",
+		AppID:    "test-app",
+		Services: []licenser.Service{{ID: "test", Name: "Test"}},
+	}
+
+	signedLicense, err := manager.GenerateLicense(&license)
+	if err != nil {
+		t.Fatalf("Failed to generate license: %v", err)
+	}
+
+	hasError := func(result *licenser.ValidationResult, target error) bool {
+		for _, e := range result.Errors {
+			if contains(e, target.Error()) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	t.Run("KeyTypeMismatch", func(t *testing.T) {
+		forged := *signedLicense
+		forged.Algorithm = licenser.AlgorithmEdDSA
+
+		result := manager.ValidateLicense(&forged)
+		if result.Valid {
+			t.Error("License with mismatched algorithm should be invalid")
+		}
+
+		if !hasError(result, licenser.ErrAlgorithmMismatch) {
+			t.Errorf("Expected algorithm mismatch error, got %v", result.Errors)
+		}
+	})
+
+	t.Run("UnknownAlgorithm", func(t *testing.T) {
+		for _, algorithm := range []string{"none", "HS256", ""} {
+			forged := *signedLicense
+			forged.Algorithm = algorithm
+
+			if manager.ValidateLicense(&forged).Valid {
+				t.Errorf("License with algorithm '%s' should be invalid", algorithm)
+			}
+		}
+	})
+
+	t.Run("AllowList", func(t *testing.T) {
+		validator, err := licenser.NewManager(licenser.Config{
+			PublicKeyPEM:      exportPublicKey(t, manager),
+			Algorithm:         licenser.AlgorithmPS256,
+			AllowedAlgorithms: []string{licenser.AlgorithmPS256},
+		})
+		if err != nil {
+			t.Fatalf("Failed to create validator: %v", err)
+		}
+
+		result := validator.ValidateLicense(signedLicense)
+		if result.Valid {
+			t.Error("RS256 license should be rejected when only PS256 is allowed")
+		}
+
+		if !hasError(result, licenser.ErrAlgorithmNotAllowed) {
+			t.Errorf("Expected algorithm not allowed error, got %v", result.Errors)
+		}
+	})
+
+	t.Run("ConfiguredAlgorithmNotAllowed", func(t *testing.T) {
+		_, err := licenser.NewManager(licenser.Config{
+			PublicKeyPEM:      exportPublicKey(t, manager),
+			AllowedAlgorithms: []string{licenser.AlgorithmPS256},
+		})
+		if !errors.Is(err, licenser.ErrAlgorithmNotAllowed) {
+			t.Errorf("Expected ErrAlgorithmNotAllowed, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,152 @@
+/*******************************************************************
+
+		::          ::        +--------+-----------------------+
+		  ::      ::          | Author | Dmitry Novikov        |
+		::::::::::::::        | Email  | dredfort.42@gmail.com |
+	  ::::  ::::::  ::::      +--------+-----------------------+
+	::::::::::::::::::::::
+	::  ::::::::::::::  ::    File     | validation.go
+	::  ::          ::  ::    Created  | 2026-10-16
+		  ::::  ::::          Modified | 2026-10-16
+
+	GitHub:   https://github.com/dredfort42
+	LinkedIn: https://linkedin.com/in/novikov-da
+
+*******************************************************************/
+
+package licenser
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Severities of validation issues.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Stable codes of validation issues. Unlike the messages they never change,
+// so callers can match and localize them.
+const (
+	IssueInvalidPayload      = "invalid_payload"
+	IssueUnsupportedFormat   = "unsupported_format"
+	IssuePayloadMismatch     = "payload_mismatch"
+	IssueUnknownKeyID        = "unknown_key_id"
+	IssueAlgorithmNotAllowed = "algorithm_not_allowed"
+	IssueAlgorithmMismatch   = "algorithm_mismatch"
+	IssueSignatureInvalid    = "signature_invalid"
+	IssueKeyRetired          = "key_retired"
+	IssueKeyRetiring         = "key_retiring"
+	IssueWeakKey             = "weak_key"
+	IssueDeprecatedAlgorithm = "deprecated_algorithm"
+	IssueLicenseExpired      = "license_expired"
+	IssueLicenseInGrace      = "license_in_grace"
+	IssueLicenseExpiringSoon = "license_expiring_soon"
+	IssueLicenseNotYetValid  = "license_not_yet_valid"
+	IssueCustomerRequired    = "customer_required"
+	IssueAppIDRequired       = "app_id_required"
+	IssueServicesRequired    = "services_required"
+	IssueVersionMissing      = "version_missing"
+)
+
+// ValidationIssue is a single problem found while validating a license. It
+// implements error and unwraps to the sentinel describing the problem, such
+// as ErrLicenseExpired, so errors.Is works on it.
+type ValidationIssue struct {
+	Code     string `json:"code"`            // Stable issue code, one of the Issue constants
+	Severity string `json:"severity"`        // SeverityError or SeverityWarning
+	Field    string `json:"field,omitempty"` // JSON path of the offending field, e.g. data.expires_at
+	Message  string `json:"message"`         // English description, as in Errors or Warnings
+	Err      error  `json:"-"`               // Wrapped sentinel error, if any
+}
+
+// Error returns the issue message.
+func (i ValidationIssue) Error() string {
+	return i.Message
+}
+
+// Unwrap returns the wrapped sentinel error.
+func (i ValidationIssue) Unwrap() error {
+	return i.Err
+}
+
+// Err returns the error issues of the result joined into one error, or nil
+// if there are none.
+func (r *ValidationResult) Err() error {
+	var errs []error
+
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			errs = append(errs, issue)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// HasIssue reports whether the result contains an issue with the given code.
+func (r *ValidationResult) HasIssue(code string) bool {
+	for _, issue := range r.Issues {
+		if issue.Code == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addError records a failed check and marks the result invalid.
+func (r *ValidationResult) addError(code, field string, err error, message string) {
+	r.Valid = false
+	r.Errors = append(r.Errors, message)
+	r.Issues = append(r.Issues, ValidationIssue{
+		Code:     code,
+		Severity: SeverityError,
+		Field:    field,
+		Message:  message,
+		Err:      err,
+	})
+}
+
+// addWarning records a condition that does not invalidate the license.
+func (r *ValidationResult) addWarning(code, field string, err error, message string) {
+	r.Warnings = append(r.Warnings, message)
+	r.Issues = append(r.Issues, ValidationIssue{
+		Code:     code,
+		Severity: SeverityWarning,
+		Field:    field,
+		Message:  message,
+		Err:      err,
+	})
+}
+
+// addPayloadError records a license whose signed payload cannot be decoded.
+func (r *ValidationResult) addPayloadError(err error) {
+	code, field := IssueInvalidPayload, "payload"
+	if errors.Is(err, ErrUnsupportedFormat) {
+		code, field = IssueUnsupportedFormat, "format"
+	}
+
+	r.addError(code, field, err, err.Error())
+}
+
+// addSignatureError records a license whose signature was not accepted.
+func (r *ValidationResult) addSignatureError(err error) {
+	switch {
+	case errors.Is(err, ErrUnknownKeyID):
+		r.addError(IssueUnknownKeyID, "key_id", err, err.Error())
+	case errors.Is(err, ErrAlgorithmNotAllowed):
+		r.addError(IssueAlgorithmNotAllowed, "algorithm", err, err.Error())
+	case errors.Is(err, ErrAlgorithmMismatch):
+		r.addError(IssueAlgorithmMismatch, "algorithm", err, err.Error())
+	case errors.Is(err, ErrSignatureVerification):
+		r.addError(IssueSignatureInvalid, "signature", err, "signature verification failed")
+	case errors.Is(err, ErrInvalidSignature):
+		r.addError(IssueSignatureInvalid, "signature",
+			fmt.Errorf("%w: %w", ErrSignatureVerification, err), "signature verification failed")
+	default:
+		r.addError(IssueSignatureInvalid, "signature", fmt.Errorf("%w: %w", ErrSignatureVerification, err), err.Error())
+	}
+}
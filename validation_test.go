@@ -0,0 +1,155 @@
+/*******************************************************************
+
+		::          ::        +--------+-----------------------+
+		  ::      ::          | Author | Dmitry Novikov        |
+		::::::::::::::        | Email  | dredfort.42@gmail.com |
+	  ::::  ::::::  ::::      +--------+-----------------------+
+	::::::::::::::::::::::
+	::  ::::::::::::::  ::    File     | validation_test.go
+	::  ::          ::  ::    Created  | 2026-10-16
+		  ::::  ::::          Modified | 2026-10-16
+
+	GitHub:   https://github.com/dredfort42
+	LinkedIn: https://linkedin.com/in/novikov-da
+
+*******************************************************************/
+
+package licenser_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	licenser "github.com/dredfort42/go_licenser"
+)
+
+func TestValidationIssues(t *testing.T) {
+	now := time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC)
+	manager := newEd25519Manager(t, licenser.Config{Clock: licenser.FixedClock(now)})
+
+	newSignedLicense := func(t *testing.T, license licenser.License) *licenser.SignedLicense {
+		t.Helper()
+
+		signedLicense, err := manager.GenerateLicense(&license)
+		if err != nil {
+			t.Fatalf("Failed to generate license: %v", err)
+		}
+
+		return signedLicense
+	}
+
+	license := licenser.License{
+		Customer: "Issue Customer",
+		AppID:    "issue-app",
+		Services: []licenser.Service{{ID: "test", Name: "Test"}},
+		Version:  "1.0.0",
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		result := manager.ValidateLicense(newSignedLicense(t, license))
+		if !result.Valid || len(result.Issues) != 0 || result.Err() != nil {
+			t.Errorf("Expected no issues, got %v", result.Issues)
+		}
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		expired := license
+		expired.ExpiresAt = now.Add(-time.Hour).Unix()
+
+		result := manager.ValidateLicense(newSignedLicense(t, expired))
+		if result.Valid || !result.HasIssue(licenser.IssueLicenseExpired) {
+			t.Fatalf("Expected an expired issue, got %v", result.Issues)
+		}
+
+		issue := result.Issues[0]
+		if issue.Severity != licenser.SeverityError || issue.Field != "data.expires_at" || issue.Message != result.Errors[0] {
+			t.Errorf("Unexpected issue: %+v", issue)
+		}
+
+		if !errors.Is(result.Err(), licenser.ErrLicenseExpired) || !errors.Is(issue, licenser.ErrLicenseExpired) {
+			t.Errorf("Expected ErrLicenseExpired, got %v", result.Err())
+		}
+	})
+
+	t.Run("Signature", func(t *testing.T) {
+		signedLicense := newSignedLicense(t, license)
+		signedLicense.Signature = "AAAA"
+
+		result := manager.ValidateLicense(signedLicense)
+		if !result.HasIssue(licenser.IssueSignatureInvalid) {
+			t.Fatalf("Expected a signature issue, got %v", result.Issues)
+		}
+
+		if !errors.Is(result.Err(), licenser.ErrSignatureVerification) {
+			t.Errorf("Expected ErrSignatureVerification, got %v", result.Err())
+		}
+
+		signedLicense.Signature = "not base64!"
+
+		if err := manager.ValidateLicense(signedLicense).Err(); !errors.Is(err, licenser.ErrSignatureVerification) ||
+			!errors.Is(err, licenser.ErrInvalidSignature) {
+			t.Errorf("Expected ErrSignatureVerification and ErrInvalidSignature, got %v", err)
+		}
+	})
+
+	t.Run("UnknownKeyID", func(t *testing.T) {
+		signedLicense := newSignedLicense(t, license)
+		signedLicense.KeyID = "unknown"
+
+		result := manager.ValidateLicense(signedLicense)
+		if !result.HasIssue(licenser.IssueUnknownKeyID) || !errors.Is(result.Err(), licenser.ErrUnknownKeyID) {
+			t.Errorf("Expected an unknown key ID issue, got %v", result.Issues)
+		}
+	})
+
+	t.Run("RequiredFields", func(t *testing.T) {
+		signedLicense := newSignedLicense(t, license)
+		signedLicense.Payload = ""
+		signedLicense.Data.Customer = ""
+		signedLicense.Data.Version = ""
+
+		result := manager.ValidateLicense(signedLicense)
+
+		for _, code := range []string{
+			licenser.IssueSignatureInvalid,
+			licenser.IssueCustomerRequired,
+			licenser.IssueVersionMissing,
+		} {
+			if !result.HasIssue(code) {
+				t.Errorf("Expected issue %s, got %v", code, result.Issues)
+			}
+		}
+
+		if !errors.Is(result.Err(), licenser.ErrCustomerRequired) {
+			t.Errorf("Expected ErrCustomerRequired, got %v", result.Err())
+		}
+
+		if len(result.Issues) != len(result.Errors)+len(result.Warnings) {
+			t.Errorf("Expected one issue per error and warning, got %d issues", len(result.Issues))
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		notYetValid := license
+		notYetValid.NotBefore = now.Add(time.Hour).Unix()
+
+		data, err := json.Marshal(manager.ValidateLicense(newSignedLicense(t, notYetValid)))
+		if err != nil {
+			t.Fatalf("Failed to marshal result: %v", err)
+		}
+
+		var decoded struct {
+			Issues []map[string]string `json:"issues"`
+		}
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Failed to unmarshal result: %v", err)
+		}
+
+		if len(decoded.Issues) != 1 || decoded.Issues[0]["code"] != licenser.IssueLicenseNotYetValid ||
+			decoded.Issues[0]["field"] != "data.not_before" || decoded.Issues[0]["severity"] != licenser.SeverityError {
+			t.Errorf("Unexpected issues JSON: %s", data)
+		}
+	})
+}
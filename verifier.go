@@ -0,0 +1,398 @@
+/*******************************************************************
+
+		::          ::        +--------+-----------------------+
+		  ::      ::          | Author | Dmitry Novikov        |
+		::::::::::::::        | Email  | dredfort.42@gmail.com |
+	  ::::  ::::::  ::::      +--------+-----------------------+
+	::::::::::::::::::::::
+	::  ::::::::::::::  ::    File     | verifier.go
+	::  ::          ::  ::    Created  | 2026-10-16
+		  ::::  ::::          Modified | 2026-10-16
+
+	GitHub:   https://github.com/dredfort42
+	LinkedIn: https://linkedin.com/in/novikov-da
+
+*******************************************************************/
+
+package licenser
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+)
+
+// Verifier validates licenses. It never holds a private key, so products
+// that only check licenses can depend on it without pulling in issuance.
+type Verifier struct {
+	publicKey  crypto.PublicKey
+	primaryKey *verificationKey
+	keys       map[string]*verificationKey
+	config     Config
+	algorithm  string // Config.Algorithm as given, before it is guessed from the key
+}
+
+// NewVerifier creates a license verifier from the public key settings of
+// config: Verifier, PublicKeyPEM, PublicKeyPath, TrustedKeys and
+// TrustedKeysJWKSPath. Private key settings are ignored.
+func NewVerifier(config Config) (*Verifier, error) {
+	return newVerifier(config.withDefaults(), nil)
+}
+
+// newVerifier creates a verifier that falls back to publicKey when config
+// names no public key of its own.
+func newVerifier(config Config, publicKey crypto.PublicKey) (*Verifier, error) {
+	v := &Verifier{publicKey: publicKey, config: config, algorithm: config.Algorithm}
+
+	var err error
+
+	// Load public key if specified separately
+	if config.PublicKeyPEM != "" {
+		v.publicKey, err = parsePublicKeyFromPEM(config.PublicKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+	} else if config.PublicKeyPath != "" {
+		v.publicKey, err = loadPublicKeyFromFile(config.FS, config.PublicKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load public key: %w", err)
+		}
+	}
+
+	if v.publicKey == nil && config.Verifier == nil && len(config.TrustedKeys) == 0 && config.TrustedKeysJWKSPath == "" {
+		return nil, ErrNoPublicKey
+	}
+
+	if err = v.checkConfiguredAlgorithm(); err != nil {
+		return nil, err
+	}
+
+	if err = v.setupKeyRing(); err != nil {
+		return nil, fmt.Errorf("failed to setup key ring: %w", err)
+	}
+
+	return v, nil
+}
+
+// checkConfiguredAlgorithm resolves the default algorithm and makes sure it
+// is allowed and usable with the primary key.
+func (v *Verifier) checkConfiguredAlgorithm() error {
+	switch {
+	case v.config.Algorithm != "":
+	case v.config.Verifier != nil:
+		v.config.Algorithm = v.config.Verifier.Algorithm()
+	case v.publicKey != nil:
+		v.config.Algorithm = algorithmForKey(v.publicKey)
+	default:
+		return nil
+	}
+
+	if !v.algorithmAllowed(v.config.Algorithm) {
+		return fmt.Errorf("%w: %s", ErrAlgorithmNotAllowed, v.config.Algorithm)
+	}
+
+	if v.config.Verifier != nil || v.publicKey == nil {
+		return nil
+	}
+
+	if err := checkKeyAlgorithm(v.publicKey, v.config.Algorithm); err != nil {
+		return fmt.Errorf("failed to setup verifier: %w", err)
+	}
+
+	return nil
+}
+
+// ValidateLicense validates a signed license as of Config.Clock's current time.
+func (v *Verifier) ValidateLicense(signedLicense *SignedLicense) *ValidationResult {
+	return v.ValidateLicenseAt(signedLicense, v.config.Clock.Now())
+}
+
+// ValidateLicenseAt validates a signed license as of the given time, for
+// example to audit whether a license was valid on a past date.
+func (v *Verifier) ValidateLicenseAt(signedLicense *SignedLicense, at time.Time) *ValidationResult {
+	result := &ValidationResult{Valid: true}
+
+	// Verify signature over the exact signed bytes
+	data, license, err := decodePayload(signedLicense)
+	if err != nil {
+		result.addPayloadError(err)
+
+		return result
+	}
+
+	if license != &signedLicense.Data && !sameLicense(license, &signedLicense.Data) {
+		result.addError(IssuePayloadMismatch, "data", ErrPayloadMismatch, ErrPayloadMismatch.Error())
+	}
+
+	key, err := v.verifySignature(signedLicense, data)
+	if err != nil {
+		result.addSignatureError(err)
+	} else {
+		key.checkRetirement(license, at, result)
+		key.checkKeySize(result)
+		v.checkDeprecatedAlgorithm(signedLicense.Algorithm, result)
+	}
+
+	// Check expiration and activation
+	switch v.window().statusAt(license, at) {
+	case StatusExpired:
+		result.addError(IssueLicenseExpired, "data.expires_at", ErrLicenseExpired, "license has expired")
+	case StatusGrace:
+		result.addWarning(IssueLicenseInGrace, "data.expires_at", ErrLicenseExpired,
+			fmt.Sprintf("license expired on %s, grace period ends on %s",
+				time.Unix(license.ExpiresAt, 0).Format(TimestampLayout),
+				v.window().graceEndsAt(license).Format(TimestampLayout)))
+	case StatusNotYetValid:
+		result.addError(IssueLicenseNotYetValid, "data.not_before", ErrLicenseNotYetValid, ErrLicenseNotYetValid.Error())
+	case StatusActive:
+		window := v.config.ExpiryWarningWindow
+		if window > 0 && IsExpiringSoon(license, window, WithClock(FixedClock(at))) {
+			expiresAt := time.Unix(license.ExpiresAt, 0)
+
+			// A license past its expiry is still active within ClockSkew.
+			if expiresAt.Before(at) {
+				result.addWarning(IssueLicenseExpiringSoon, "data.expires_at", nil,
+					fmt.Sprintf("license expired on %s, accepted within clock skew", expiresAt.Format(TimestampLayout)))
+			} else {
+				result.addWarning(IssueLicenseExpiringSoon, "data.expires_at", nil, fmt.Sprintf("license expires on %s (in %s)",
+					expiresAt.Format(TimestampLayout), formatDuration(expiresAt.Sub(at))))
+			}
+		}
+	}
+
+	// Basic validation
+	if license.Customer == "" {
+		result.addError(IssueCustomerRequired, "data.customer", ErrCustomerRequired, "customer is required")
+	}
+
+	if license.AppID == "" {
+		result.addError(IssueAppIDRequired, "data.app_id", ErrAppIDRequired, "app ID is required")
+	}
+
+	if len(license.Services) == 0 {
+		result.addError(IssueServicesRequired, "data.services", ErrNoServicesAllowed, "at least one service is required")
+	}
+
+	// Optional fields
+	if license.Version == "" {
+		result.addWarning(IssueVersionMissing, "data.version", nil, "license has no version")
+	}
+
+	return result
+}
+
+// LoadLicense loads a license from file. JSON, PEM-armored and COSE licenses
+// are detected automatically.
+func (v *Verifier) LoadLicense(filePath string) (*SignedLicense, error) {
+	// #nosec G304
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read license file: %w", err)
+	}
+	defer file.Close()
+
+	return v.ReadLicense(file)
+}
+
+// LoadAndValidateLicense loads and validates a license in one call.
+func (v *Verifier) LoadAndValidateLicense(filePath string) (*SignedLicense, *ValidationResult, error) {
+	signedLicense, err := v.LoadLicense(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := v.ValidateLicense(signedLicense)
+
+	return signedLicense, result, nil
+}
+
+// SavePublicKey saves the public key to a file.
+func (v *Verifier) SavePublicKey(filePath string) error {
+	if v.publicKey == nil {
+		return ErrNoPublicKey
+	}
+
+	return writeFile(filePath, v.config.PublicKeyFileMode, true, v.WritePublicKey)
+}
+
+// ExportPublicKey exports the public key as PEM. Verifiers backed only by a
+// custom SignatureVerifier or trusted keys return ErrNoPublicKey.
+func (v *Verifier) ExportPublicKey() (string, error) {
+	return encodePublicKeyPEM(v.publicKey)
+}
+
+// GetPublicKey returns the RSA public key, or nil if the verifier uses another key type.
+func (v *Verifier) GetPublicKey() *rsa.PublicKey {
+	rsaPub, _ := v.publicKey.(*rsa.PublicKey)
+
+	return rsaPub
+}
+
+// PublicKey returns the public key used for validation.
+func (v *Verifier) PublicKey() crypto.PublicKey {
+	return v.publicKey
+}
+
+// IsExpired checks if a license is expired, including its grace period.
+func (v *Verifier) IsExpired(license *License) bool {
+	return v.status(license) == StatusExpired
+}
+
+// IsNotYetValid checks if a license has not reached its activation time.
+func (v *Verifier) IsNotYetValid(license *License) bool {
+	return v.status(license) == StatusNotYetValid
+}
+
+// InGracePeriod checks if a license has expired but is still accepted
+// because of Config.GracePeriod.
+func (v *Verifier) InGracePeriod(license *License) bool {
+	return v.status(license) == StatusGrace
+}
+
+// IsActive checks if a license is currently active. Licenses in their
+// grace period are active.
+func (v *Verifier) IsActive(license *License) bool {
+	status := v.status(license)
+
+	return status == StatusActive || status == StatusGrace
+}
+
+// status returns the status of license at Config.Clock's current time.
+func (v *Verifier) status(license *License) string {
+	return v.window().statusAt(license, v.config.Clock.Now())
+}
+
+// window returns the configured leeway around validity periods.
+func (v *Verifier) window() validityWindow {
+	return validityWindow{skew: v.config.ClockSkew, grace: v.config.GracePeriod}
+}
+
+// CheckExpiration returns an error if the license is expired.
+func (v *Verifier) CheckExpiration(license *License) error {
+	if v.IsExpired(license) {
+		return ErrLicenseExpired
+	}
+
+	return nil
+}
+
+// CheckValidityPeriod returns ErrLicenseExpired or ErrLicenseNotYetValid if
+// the license is outside its validity period.
+func (v *Verifier) CheckValidityPeriod(license *License) error {
+	if v.IsExpired(license) {
+		return ErrLicenseExpired
+	}
+
+	if v.IsNotYetValid(license) {
+		return ErrLicenseNotYetValid
+	}
+
+	return nil
+}
+
+// GetLicenseInfo creates formatted license information.
+func (v *Verifier) GetLicenseInfo(license *License) *LicenseInfo {
+	now := v.config.Clock.Now()
+
+	info := &LicenseInfo{
+		Customer:    license.Customer,
+		AppID:       license.AppID,
+		IssuedAt:    time.Unix(license.IssuedAt, 0),
+		Services:    license.Services,
+		Limits:      license.Limits,
+		Features:    license.Features,
+		Metadata:    license.Metadata,
+		Version:     license.Version,
+		Environment: license.Environment,
+		Extensions:  license.Extensions,
+	}
+
+	if license.NotBefore > 0 {
+		notBefore := time.Unix(license.NotBefore, 0)
+		info.NotBefore = &notBefore
+	}
+
+	info.Status = v.window().statusAt(license, now)
+
+	if license.ExpiresAt > 0 {
+		expiresAt := time.Unix(license.ExpiresAt, 0)
+		info.ExpiresAt = &expiresAt
+
+		switch info.Status {
+		case StatusExpired:
+			info.TimeUntilExpiry = LicenseExpired
+		case StatusGrace:
+			graceEndsAt := v.window().graceEndsAt(license)
+			info.GraceEndsAt = &graceEndsAt
+			info.TimeUntilExpiry = LicenseExpired
+		default:
+			// Within ClockSkew after expiry the license is still active, so
+			// report no time left rather than LicenseExpired.
+			remaining := max(expiresAt.Sub(now), 0)
+			info.TimeUntilExpiry = formatDuration(remaining)
+		}
+	} else {
+		info.TimeUntilExpiry = LicenseNeverExpired
+	}
+
+	return info
+}
+
+// verifySignature verifies the license signature and returns the key that produced it.
+func (v *Verifier) verifySignature(signedLicense *SignedLicense, data []byte) (*verificationKey, error) {
+	key, err := v.lookupKey(signedLicense.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := v.checkAlgorithm(key, signedLicense.Algorithm); err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(signedLicense.Signature)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	if err := key.verify(data, signature, signedLicense.Algorithm); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// checkAlgorithm rejects declared algorithms that are not allowed or do not
+// match the verification key, so a forged license cannot pick a weaker scheme.
+func (v *Verifier) checkAlgorithm(key *verificationKey, algorithm string) error {
+	if algorithm == "" {
+		return fmt.Errorf("%w: no algorithm declared", ErrAlgorithmMismatch)
+	}
+
+	if !v.algorithmAllowed(algorithm) {
+		return fmt.Errorf("%w: %s", ErrAlgorithmNotAllowed, algorithm)
+	}
+
+	return key.checkAlgorithm(algorithm)
+}
+
+// checkDeprecatedAlgorithm warns about licenses signed with an algorithm
+// listed in Config.DeprecatedAlgorithms.
+func (v *Verifier) checkDeprecatedAlgorithm(algorithm string, result *ValidationResult) {
+	if slices.Contains(v.config.DeprecatedAlgorithms, algorithm) {
+		result.addWarning(IssueDeprecatedAlgorithm, "algorithm", nil,
+			fmt.Sprintf("license is signed with deprecated algorithm %s", algorithm))
+	}
+}
+
+func (v *Verifier) algorithmAllowed(algorithm string) bool {
+	allowed := v.config.AllowedAlgorithms
+	if len(allowed) == 0 {
+		allowed = supportedAlgorithms
+	}
+
+	return slices.Contains(allowed, algorithm)
+}